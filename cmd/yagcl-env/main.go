@@ -0,0 +1,220 @@
+// yagcl-env is a helper for go generate, producing files derived from
+// configuration structs.
+//
+// Usage:
+//
+//	//go:generate go run github.com/Bios-Marcel/yagcl-env/cmd/yagcl-env example -prefix APP -o .env.example . Config
+//	//go:generate go run github.com/Bios-Marcel/yagcl-env/cmd/yagcl-env parser -prefix APP -func ParseEnv -o config_env.go . Config
+//
+// Since the configuration struct has to be inspected via reflection, a
+// temporary program importing the given package is built and run. Therefore
+// the package can't be a main package and the module containing it has to
+// depend on github.com/Bios-Marcel/yagcl-env. The program is built with the
+// build tag env.GeneratorBuildTag, which excludes previously generated
+// parsers, so that outdated ones can't break the build. Consequently, the
+// package must compile without them.
+//
+// Generated parsers don't support validation tags, such as `required`, `min`
+// or `oneof`. The parser command fails for structs using them; use the
+// reflection based source for those instead.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "yagcl-env:", err)
+		os.Exit(1)
+	}
+}
+
+const usage = `usage: yagcl-env <command> [flags] <package> <type>
+
+commands:
+  example  writes a documented .env example file
+  parser   writes a parser function that doesn't use reflection; structs
+           using validation tags aren't supported`
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "example":
+		return runExample(args[1:])
+	case "parser":
+		return runParser(args[1:])
+	}
+	return fmt.Errorf("unknown command '%s'\n%s", args[0], usage)
+}
+
+func runExample(args []string) error {
+	flags := flag.NewFlagSet("example", flag.ContinueOnError)
+	prefix := flags.String("prefix", "", "prefix for all keys")
+	output := flags.String("o", ".env.example", "output file, - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New(usage)
+	}
+
+	result, err := runReflectProgram(flags.Arg(0), flags.Arg(1), `
+	if err := env.WriteExample(os.Stdout, &value, env.WithPrefix({{printf "%q" .Prefix}})); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}`, map[string]any{"Prefix": *prefix})
+	if err != nil {
+		return err
+	}
+	return writeOutput(*output, result)
+}
+
+func runParser(args []string) error {
+	flags := flag.NewFlagSet("parser", flag.ContinueOnError)
+	prefix := flags.String("prefix", "", "prefix for all keys")
+	functionName := flags.String("func", "ParseEnv", "name of the generated function")
+	output := flags.String("o", "", "output file, - for stdout; defaults to <type>_env.go")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New(usage)
+	}
+
+	packageName, err := resolvePackageName(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	result, err := runReflectProgram(flags.Arg(0), flags.Arg(1), `
+	if err := env.WriteParser(os.Stdout, &value, {{printf "%q" .PackageName}}, {{printf "%q" .FunctionName}}, env.WithPrefix({{printf "%q" .Prefix}})); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}`, map[string]any{
+		"Prefix":       *prefix,
+		"PackageName":  packageName,
+		"FunctionName": *functionName,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		*output = strings.ToLower(flags.Arg(1)) + "_env.go"
+	}
+	return writeOutput(*output, result)
+}
+
+var programTemplate = template.Must(template.New("program").Parse(`// Code generated by yagcl-env. DO NOT EDIT.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+	target {{printf "%q" .ImportPath}}
+)
+
+var _ = fmt.Sprint
+
+func main() {
+	var value target.{{.TypeName}}
+{{.Body}}
+}
+`))
+
+// runReflectProgram builds and runs a temporary program, that declares a
+// variable `value` of the given type and runs the given body, which is a
+// template itself, receiving the given data. The output of the program is
+// returned.
+func runReflectProgram(packagePath, typeName, body string, data map[string]any) ([]byte, error) {
+	importPath, err := resolveImportPath(packagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var renderedBody bytes.Buffer
+	if err := template.Must(template.New("body").Parse(body)).Execute(&renderedBody, data); err != nil {
+		return nil, err
+	}
+
+	var program bytes.Buffer
+	if err := programTemplate.Execute(&program, map[string]any{
+		"ImportPath": importPath,
+		"TypeName":   typeName,
+		"Body":       renderedBody.String(),
+	}); err != nil {
+		return nil, err
+	}
+
+	// The program has to be placed inside the current module, so that the
+	// import can be resolved.
+	directory, err := os.MkdirTemp(".", ".yagcl-env-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(directory)
+	if err := os.WriteFile(filepath.Join(directory, "main.go"), program.Bytes(), 0o600); err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	command := exec.Command("go", "run", "-tags", env.GeneratorBuildTag, "./"+filepath.Base(directory))
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return nil, fmt.Errorf("running reflection program failed: %w\n%s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+// resolveImportPath turns relative package paths into import paths.
+func resolveImportPath(packagePath string) (string, error) {
+	if !strings.HasPrefix(packagePath, ".") {
+		return packagePath, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	command := exec.Command("go", "list", "-f", "{{.ImportPath}}", packagePath)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("resolving package '%s' failed: %w\n%s", packagePath, err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// resolvePackageName returns the name of the package, which may differ
+// from the last element of its path.
+func resolvePackageName(packagePath string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	command := exec.Command("go", "list", "-f", "{{.Name}}", packagePath)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("resolving package '%s' failed: %w\n%s", packagePath, err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func writeOutput(output string, data []byte) error {
+	if output == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(output, data, 0o644)
+}
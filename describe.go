@@ -0,0 +1,178 @@
+package env
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// descriptionTagNames are the tags that can be used to document a field.
+// The first non-empty one is used.
+var descriptionTagNames = []string{"desc", "doc"}
+
+// Variable describes a single environment variable derived from a
+// configuration struct.
+type Variable struct {
+	// Key is the fully joined key of the variable.
+	Key string `json:"key"`
+	// Type is the Go type of the field.
+	Type string `json:"type"`
+	// Separator separates the elements of slices, arrays and maps.
+	Separator string `json:"separator,omitempty"`
+	// KeyValueSeparator separates keys from values in map entries.
+	KeyValueSeparator string `json:"keyValueSeparator,omitempty"`
+	// AllowedValues are the values defined via the `oneof` tag.
+	AllowedValues []string `json:"allowedValues,omitempty"`
+	// Default is the default value in its textual form, taken from the
+	// pre-populated struct or the `default` tag. For secret fields, this is
+	// RedactedValue. Nil if there's no default.
+	Default *string `json:"default,omitempty"`
+	// Required is true if the key has to be present in the source, or if the
+	// value has to be non-zero.
+	Required bool `json:"required"`
+	// Secret is true if the field or any struct containing it is marked via
+	// `secret:"true"`.
+	Secret bool `json:"secret"`
+	// Description is taken from the `desc` or `doc` tag.
+	Description string `json:"description,omitempty"`
+}
+
+// Variables is a list of variables as returned by Describe.
+type Variables []Variable
+
+// Describe returns all variables recognized for the given configuration
+// struct, in the order of the struct fields. Nested structs, including nil
+// pointers to structs, are walked the same way as when parsing. Defaults are
+// taken from non-zero values of the given struct or from the `default` tag.
+//
+// The keys are derived the same way as in the source created by Source,
+// which can be configured using the given options.
+func Describe(configurationStruct any, opts ...Option) (Variables, error) {
+	o := newOptions(opts)
+
+	structValue := reflect.Indirect(reflect.ValueOf(configurationStruct))
+	if structValue.Kind() != reflect.Struct {
+		return nil, yagcl.ErrInvalidConfiguraionPointer
+	}
+
+	var variables Variables
+	err := o.walk(structValue, func(field walkedField) error {
+		variable, err := describeField(field)
+		if err != nil {
+			return err
+		}
+		variables = append(variables, variable)
+		return nil
+	})
+	return variables, err
+}
+
+// describeField creates the Variable for a field found via options.walk.
+func describeField(field walkedField) (Variable, error) {
+	variable := Variable{
+		Key:  field.joinedEnvKey,
+		Type: field.structField.Type.String(),
+		Required: field.options.required ||
+			strings.EqualFold(field.structField.Tag.Get(requiredTagName), "true"),
+		Secret: field.secret,
+	}
+
+	if !isBinary(field.underlyingType, field.byteEncoding) && !field.unmarshalsText {
+		switch field.underlyingType.Kind() {
+		case reflect.Slice, reflect.Array:
+			variable.Separator = ","
+		case reflect.Map:
+			variable.Separator = ","
+			variable.KeyValueSeparator = "="
+		}
+	}
+
+	if rawAllowedValues, ok := field.structField.Tag.Lookup(oneOfTagName); ok {
+		variable.AllowedValues = splitString(rawAllowedValues, ',')
+	}
+
+	for _, tagName := range descriptionTagNames {
+		if text := field.structField.Tag.Get(tagName); text != "" {
+			variable.Description = text
+			break
+		}
+	}
+
+	if !field.absent && !field.value.IsZero() {
+		defaultValue, err := formatValue(field.structField.Name, field.value, field.byteEncoding)
+		if err != nil {
+			return Variable{}, err
+		}
+		variable.Default = &defaultValue
+	} else if defaultValue, ok := field.structField.Tag.Lookup(defaultTagName); ok {
+		variable.Default = &defaultValue
+	}
+	if variable.Secret && variable.Default != nil {
+		redacted := RedactedValue
+		variable.Default = &redacted
+	}
+
+	return variable, nil
+}
+
+// WriteJSON writes the variables as indented JSON array.
+func (v Variables) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	// Since values are often URLs, escaping would only hurt readability.
+	encoder.SetEscapeHTML(false)
+	if v == nil {
+		return encoder.Encode(Variables{})
+	}
+	return encoder.Encode(v)
+}
+
+// WriteMarkdown writes the variables as Markdown table.
+func (v Variables) WriteMarkdown(writer io.Writer) error {
+	bufferedWriter := bufio.NewWriter(writer)
+	bufferedWriter.WriteString("| Key | Type | Default | Required | Secret | Separator | Allowed values | Description |\n")
+	bufferedWriter.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, variable := range v {
+		var defaultValue string
+		if variable.Default != nil {
+			defaultValue = "`" + *variable.Default + "`"
+		}
+		separator := variable.Separator
+		if variable.KeyValueSeparator != "" {
+			separator += " " + variable.KeyValueSeparator
+		}
+		allowedValues := make([]string, 0, len(variable.AllowedValues))
+		for _, allowedValue := range variable.AllowedValues {
+			allowedValues = append(allowedValues, "`"+allowedValue+"`")
+		}
+
+		cells := []string{
+			"`" + variable.Key + "`",
+			"`" + variable.Type + "`",
+			defaultValue,
+			yesNo(variable.Required),
+			yesNo(variable.Secret),
+			separator,
+			strings.Join(allowedValues, ", "),
+			variable.Description,
+		}
+		for index, cell := range cells {
+			// Pipes would break the table and newlines aren't allowed in cells.
+			cell = strings.ReplaceAll(cell, "|", `\|`)
+			cells[index] = strings.ReplaceAll(cell, "\n", "<br>")
+		}
+		bufferedWriter.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return bufferedWriter.Flush()
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
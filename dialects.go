@@ -0,0 +1,415 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrUnsupportedDialect is thrown if an unknown Dialect has been configured.
+var ErrUnsupportedDialect = errors.New("unsupported dialect")
+
+// Dialect defines the grammar used to parse files, bytes and readers. Since
+// each runtime interprets environment files slightly differently, the dialect
+// should match the runtime the file is shared with, so that the values are
+// exactly the same.
+type Dialect string
+
+const (
+	// DialectDotenv is the default grammar, which is documented in the
+	// README. It supports quotes, escapes, comments, multiline values and
+	// variable expansion.
+	DialectDotenv Dialect = "dotenv"
+	// DialectDocker matches `docker run --env-file`. Everything after the
+	// first '=' is taken literally, including quotes and whitespace. Lines
+	// without '=' take the value from the environment of the process and are
+	// skipped if it isn't set there either.
+	DialectDocker Dialect = "docker"
+	// DialectSystemd matches `EnvironmentFile=` of systemd units. Comments
+	// start with '#' or ';', values may be quoted and continue on the next
+	// line if it ends with a backslash. Variables aren't expanded and invalid
+	// lines are ignored, just like systemd does.
+	DialectSystemd Dialect = "systemd"
+	// DialectPOSIXShell matches what a POSIX shell would assign when sourcing
+	// the file. Only assignments, optionally prefixed with "export", and
+	// comments are allowed. Values follow the shell quoting rules and expand
+	// variables, preferring previous assignments over the environment.
+	DialectPOSIXShell Dialect = "posix-shell"
+)
+
+// parseDocument parses the content using the given dialect, returning all
+// definitions in the order they appear in, including duplicate keys.
+func parseDocument(dialect Dialect, path string, content []byte) ([]variable, error) {
+	p := newDotenvParser(path, content)
+	var err error
+	switch dialect {
+	case "", DialectDotenv:
+		err = p.parse()
+	case DialectDocker:
+		err = p.parseDocker()
+	case DialectSystemd:
+		p.parseSystemd()
+	case DialectPOSIXShell:
+		p.preferDefinitions = true
+		err = p.parsePOSIXShell()
+	default:
+		return nil, fmt.Errorf("dialect '%s': %w", dialect, ErrUnsupportedDialect)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.variables, nil
+}
+
+// parseDocker mirrors the env file parser of the docker CLI.
+func (p *dotenvParser) parseDocker() error {
+	for p.offset < len(p.content) {
+		end := strings.IndexByte(p.content[p.offset:], '\n')
+		if end == -1 {
+			end = len(p.content) - p.offset
+		}
+		rawLine := strings.TrimSuffix(p.content[p.offset:p.offset+end], "\r")
+		line := strings.TrimLeft(rawLine, " \t\v\f\r")
+		lineNumber := p.line
+
+		p.offset += end + 1
+		p.line++
+		p.lineStart = p.offset
+
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(line, "=")
+		column := len([]rune(rawLine)) - len([]rune(line)) + 1
+		if key == "" {
+			return p.errorAt(lineNumber, column, "missing key")
+		}
+		if strings.ContainsAny(key, " \t") {
+			return p.errorAt(lineNumber, column, fmt.Sprintf("key '%s' contains whitespace", key))
+		}
+		if !hasValue {
+			var ok bool
+			if value, ok = os.LookupEnv(key); !ok {
+				continue
+			}
+		}
+		p.define(variable{key: key, value: value, line: lineNumber})
+	}
+	return nil
+}
+
+// systemdParserState is the state of parseSystemd.
+type systemdParserState int
+
+const (
+	systemdPreKey systemdParserState = iota
+	systemdKey
+	systemdPreValue
+	systemdValue
+	systemdValueEscape
+	systemdSingleQuoteValue
+	systemdDoubleQuoteValue
+	systemdDoubleQuoteValueEscape
+	systemdComment
+	systemdCommentEscape
+)
+
+// parseSystemd mirrors the environment file parser of systemd, which is a
+// state machine operating on single characters. It never fails, as systemd
+// ignores invalid lines.
+func (p *dotenvParser) parseSystemd() {
+	var (
+		state systemdParserState
+		key   strings.Builder
+		value strings.Builder
+		// trailingWhitespace is the length of key or value, before trailing
+		// whitespace started, or -1 if there's no trailing whitespace.
+		trailingWhitespace = -1
+		keyLine            int
+	)
+	isWhitespace := func(character byte) bool {
+		return character == ' ' || character == '\t' || character == '\r' || character == '\n'
+	}
+	isNewline := func(character byte) bool {
+		return character == '\r' || character == '\n'
+	}
+	push := func() {
+		keyValue := key.String()
+		valueValue := value.String()
+		if state == systemdValue && trailingWhitespace >= 0 {
+			valueValue = valueValue[:trailingWhitespace]
+		}
+		// systemd ignores assignments with invalid names.
+		if isVariableName(keyValue) {
+			p.define(variable{key: keyValue, value: valueValue, line: keyLine})
+		}
+		key.Reset()
+		value.Reset()
+	}
+
+	for ; p.offset < len(p.content); p.offset++ {
+		character := p.content[p.offset]
+		switch state {
+		case systemdPreKey:
+			if character == '#' || character == ';' {
+				state = systemdComment
+			} else if !isWhitespace(character) {
+				state = systemdKey
+				keyLine = p.line
+				trailingWhitespace = -1
+				key.WriteByte(character)
+			}
+		case systemdKey:
+			if isNewline(character) {
+				// Lines without '=' are ignored.
+				state = systemdPreKey
+				key.Reset()
+			} else if character == '=' {
+				if trailingWhitespace >= 0 {
+					keyValue := key.String()[:trailingWhitespace]
+					key.Reset()
+					key.WriteString(keyValue)
+				}
+				state = systemdPreValue
+				trailingWhitespace = -1
+			} else {
+				if !isWhitespace(character) {
+					trailingWhitespace = -1
+				} else if trailingWhitespace < 0 {
+					trailingWhitespace = key.Len()
+				}
+				key.WriteByte(character)
+			}
+		case systemdPreValue:
+			if isNewline(character) {
+				push()
+				state = systemdPreKey
+			} else if character == '\'' {
+				state = systemdSingleQuoteValue
+			} else if character == '"' {
+				state = systemdDoubleQuoteValue
+			} else if character == '\\' {
+				state = systemdValueEscape
+			} else if !isWhitespace(character) {
+				state = systemdValue
+				value.WriteByte(character)
+			}
+		case systemdValue:
+			if isNewline(character) {
+				push()
+				state = systemdPreKey
+			} else if character == '\\' {
+				state = systemdValueEscape
+				trailingWhitespace = -1
+			} else {
+				if !isWhitespace(character) {
+					trailingWhitespace = -1
+				} else if trailingWhitespace < 0 {
+					trailingWhitespace = value.Len()
+				}
+				value.WriteByte(character)
+			}
+		case systemdValueEscape:
+			state = systemdValue
+			// A backslash at the end of the line continues the value.
+			if !isNewline(character) {
+				value.WriteByte(character)
+			}
+		case systemdSingleQuoteValue:
+			if character == '\'' {
+				state = systemdPreValue
+			} else {
+				value.WriteByte(character)
+			}
+		case systemdDoubleQuoteValue:
+			if character == '"' {
+				state = systemdPreValue
+			} else if character == '\\' {
+				state = systemdDoubleQuoteValueEscape
+			} else {
+				value.WriteByte(character)
+			}
+		case systemdDoubleQuoteValueEscape:
+			state = systemdDoubleQuoteValue
+			if strings.IndexByte("\"\\`$", character) != -1 {
+				value.WriteByte(character)
+			} else if character != '\n' {
+				value.WriteByte('\\')
+				value.WriteByte(character)
+			}
+		case systemdComment:
+			if character == '\\' {
+				state = systemdCommentEscape
+			} else if isNewline(character) {
+				state = systemdPreKey
+			}
+		case systemdCommentEscape:
+			state = systemdComment
+		}
+
+		if character == '\n' {
+			p.line++
+		}
+	}
+
+	switch state {
+	case systemdPreValue, systemdValue, systemdValueEscape,
+		systemdSingleQuoteValue, systemdDoubleQuoteValue, systemdDoubleQuoteValueEscape:
+		push()
+	}
+}
+
+// parsePOSIXShell parses a file as a POSIX shell would, if it consists only
+// of assignments and comments. Anything else, such as commands, is rejected.
+func (p *dotenvParser) parsePOSIXShell() error {
+	for p.offset < len(p.content) {
+		p.skipWhitespace()
+		if p.peek() == ';' {
+			p.offset++
+			continue
+		}
+		if p.atLineEnd() {
+			p.skipLineEnd()
+			continue
+		}
+		if p.peek() == '#' {
+			for !p.atLineEnd() {
+				p.offset++
+			}
+			continue
+		}
+
+		definition := variable{line: p.line}
+		definition.key = p.readKey()
+		if definition.key == "export" && (p.peek() == ' ' || p.peek() == '\t') {
+			p.skipWhitespace()
+			definition.key = p.readKey()
+		}
+		if !isVariableName(definition.key) {
+			return p.errorf("expected an assignment")
+		}
+		if p.peek() != '=' {
+			return p.errorf("expected '=' directly after '%s'", definition.key)
+		}
+		p.offset++
+
+		var err error
+		if definition.value, err = p.readShellWord(); err != nil {
+			return err
+		}
+		p.define(definition)
+
+		p.skipWhitespace()
+		if !p.atLineEnd() && p.peek() != ';' && p.peek() != '#' {
+			return p.errorf("unexpected character %q after assignment to '%s'", p.peekRune(), definition.key)
+		}
+	}
+	return nil
+}
+
+// readShellWord reads a single word, which ends at unquoted whitespace.
+func (p *dotenvParser) readShellWord() (string, error) {
+	var builder strings.Builder
+	for !p.atLineEnd() {
+		switch character := p.content[p.offset]; character {
+		case ' ', '\t', ';':
+			return builder.String(), nil
+		case '\'':
+			quoteLine, quoteColumn := p.line, p.column()
+			end := strings.IndexByte(p.content[p.offset+1:], '\'')
+			if end == -1 {
+				return "", p.errorAt(quoteLine, quoteColumn, "unterminated single quoted value")
+			}
+			p.advance(p.offset + 1 + end + 1)
+			builder.WriteString(p.content[p.offset-end-1 : p.offset-1])
+		case '"':
+			if err := p.readShellDoubleQuoted(&builder); err != nil {
+				return "", err
+			}
+		case '\\':
+			p.offset++
+			if p.offset == len(p.content) {
+				continue
+			}
+			if escaped := p.content[p.offset]; escaped == '\r' || escaped == '\n' {
+				// Line continuation
+				p.skipLineEnd()
+			} else {
+				builder.WriteByte(escaped)
+				p.offset++
+			}
+		case '$':
+			if err := p.expandShell(&builder); err != nil {
+				return "", err
+			}
+		case '`', '|', '&', '<', '>', '(', ')':
+			return "", p.errorf("unsupported shell syntax %q", character)
+		default:
+			builder.WriteByte(character)
+			p.offset++
+		}
+	}
+	return builder.String(), nil
+}
+
+func (p *dotenvParser) readShellDoubleQuoted(builder *strings.Builder) error {
+	quoteLine, quoteColumn := p.line, p.column()
+	p.offset++
+	for p.offset < len(p.content) {
+		switch character := p.content[p.offset]; character {
+		case '"':
+			p.offset++
+			return nil
+		case '\\':
+			p.offset++
+			if p.offset == len(p.content) {
+				continue
+			}
+			switch escaped := p.content[p.offset]; escaped {
+			case '"', '\\', '$', '`':
+				builder.WriteByte(escaped)
+				p.offset++
+			case '\n':
+				p.skipLineEnd()
+			default:
+				builder.WriteByte('\\')
+			}
+		case '$':
+			if err := p.expandShell(builder); err != nil {
+				return err
+			}
+		case '`':
+			return p.errorf("unsupported shell syntax %q", character)
+		case '\r', '\n':
+			start := p.offset
+			p.skipLineEnd()
+			builder.WriteString(p.content[start:p.offset])
+		default:
+			builder.WriteByte(character)
+			p.offset++
+		}
+	}
+	return p.errorAt(quoteLine, quoteColumn, "unterminated double quoted value")
+}
+
+// expandShell is like expand, but rejects command substitutions.
+func (p *dotenvParser) expandShell(builder *strings.Builder) error {
+	if p.offset+1 < len(p.content) && p.content[p.offset+1] == '(' {
+		return p.errorf("unsupported command substitution")
+	}
+	return p.expand(builder)
+}
+
+// advance moves to the given offset, keeping track of the line endings in
+// between.
+func (p *dotenvParser) advance(offset int) {
+	for p.offset < offset {
+		if p.atLineEnd() {
+			p.skipLineEnd()
+		} else {
+			p.offset++
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// DifferenceKind describes how a key differs between two configurations.
+type DifferenceKind string
+
+const (
+	// DifferenceAdded means that the key only has a value in the new
+	// configuration, for example since a pointer has been set.
+	DifferenceAdded DifferenceKind = "added"
+	// DifferenceRemoved means that the key only has a value in the old
+	// configuration.
+	DifferenceRemoved DifferenceKind = "removed"
+	// DifferenceChanged means that the key has a different value in both
+	// configurations.
+	DifferenceChanged DifferenceKind = "changed"
+)
+
+// Difference describes a single key, whose value differs between two
+// configurations. Values are formatted the same way Marshal formats them.
+type Difference struct {
+	Key  string
+	Kind DifferenceKind
+	// Old is nil if the key has been added.
+	Old *string
+	// New is nil if the key has been removed.
+	New *string
+	// Secret is true if the field is marked as secret, in which case the
+	// values are replaced by RedactedValue.
+	Secret bool
+}
+
+// String formats the difference for logging.
+func (d Difference) String() string {
+	switch d.Kind {
+	case DifferenceAdded:
+		return fmt.Sprintf("+ %s=%s", d.Key, *d.New)
+	case DifferenceRemoved:
+		return fmt.Sprintf("- %s=%s", d.Key, *d.Old)
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", d.Key, *d.Old, *d.New)
+}
+
+// Diff compares two instances of the same configuration struct and returns
+// a Difference for each key, whose value differs. The keys are built the same
+// way the source created by Source builds them, which can be configured
+// using the given options. Since values are compared in their formatted
+// form, nil and empty slices or maps are considered equal, while nil
+// pointers are considered unset. Secret values are always redacted, as
+// the result is usually logged.
+func Diff(oldConfiguration, newConfiguration any, opts ...Option) ([]Difference, error) {
+	o := newOptions(opts)
+	o.redactSecrets = true
+
+	oldValue := reflect.Indirect(reflect.ValueOf(oldConfiguration))
+	newValue := reflect.Indirect(reflect.ValueOf(newConfiguration))
+	if oldValue.Kind() != reflect.Struct || newValue.Kind() != reflect.Struct {
+		return nil, yagcl.ErrInvalidConfiguraionPointer
+	}
+	if oldValue.Type() != newValue.Type() {
+		return nil, fmt.Errorf("can't compare '%s' with '%s': %w", oldValue.Type(), newValue.Type(), yagcl.ErrInvalidConfiguraionPointer)
+	}
+
+	oldValues, err := o.formattedValues(oldValue)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := o.formattedValues(newValue)
+	if err != nil {
+		return nil, err
+	}
+
+	// Since both values are of the same type, the fields are walked in the
+	// same order.
+	var differences []Difference
+	for index, oldField := range oldValues {
+		newField := newValues[index]
+		difference := Difference{
+			Key:    oldField.field.joinedEnvKey,
+			Old:    oldField.value,
+			New:    newField.value,
+			Secret: oldField.field.secret,
+		}
+		switch {
+		case oldField.value == nil && newField.value == nil:
+			continue
+		case oldField.value == nil:
+			difference.Kind = DifferenceAdded
+		case newField.value == nil:
+			difference.Kind = DifferenceRemoved
+		case *oldField.value != *newField.value:
+			difference.Kind = DifferenceChanged
+		case oldField.field.secret:
+			// Since the redacted values are equal, we have to compare the
+			// actual values.
+			changed, err := secretChanged(oldField.field, newField.field)
+			if err != nil {
+				return nil, err
+			}
+			if !changed {
+				continue
+			}
+			difference.Kind = DifferenceChanged
+		default:
+			continue
+		}
+		differences = append(differences, difference)
+	}
+
+	return differences, nil
+}
+
+type formattedValue struct {
+	field walkedField
+	// value is nil if the field is absent or a nil pointer.
+	value *string
+}
+
+func (o *options) formattedValues(structValue reflect.Value) ([]formattedValue, error) {
+	var values []formattedValue
+	err := o.walk(structValue, func(field walkedField) error {
+		value := formattedValue{field: field}
+		if !field.absent {
+			formatted, err := o.formatField(field)
+			if err != nil {
+				return err
+			}
+			value.value = formatted
+		}
+		values = append(values, value)
+		return nil
+	})
+	return values, err
+}
+
+func secretChanged(oldField, newField walkedField) (bool, error) {
+	byteEncoding := oldField.byteEncoding
+	oldValue, err := formatValue(oldField.structField.Name, oldField.value, byteEncoding)
+	if err != nil {
+		return false, err
+	}
+	newValue, err := formatValue(newField.structField.Name, newField.value, byteEncoding)
+	if err != nil {
+		return false, err
+	}
+	return oldValue != newValue, nil
+}
@@ -0,0 +1,84 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// discoveryMarkers are files and directories marking the root of a project.
+// Discovery doesn't search beyond a directory containing one of them.
+var discoveryMarkers = []string{"go.mod", ".git"}
+
+// Discover implements EnvSourceSetupStepOne.Discover.
+func (s *envSourceImpl) Discover(name string) EnvSourceSetupStepTwoEnvFile[*envSourceImpl] {
+	return s.DiscoverFrom("", name)
+}
+
+// DiscoverFrom implements EnvSourceSetupStepOne.DiscoverFrom.
+func (s *envSourceImpl) DiscoverFrom(directory, name string) EnvSourceSetupStepTwoEnvFile[*envSourceImpl] {
+	s.discoverFrom = directory
+	s.discoverName = name
+	return s
+}
+
+// resolve returns the source actually reading the data. For sources created
+// via Discover, this is a copy reading the discovered file, as discovery is
+// repeated on each call to Parse.
+func (s *envSourceImpl) resolve() (*envSourceImpl, error) {
+	if s.discoverName == "" {
+		return s, nil
+	}
+
+	path, err := discoverFile(s.discoverFrom, s.discoverName)
+	if err != nil {
+		return nil, err
+	}
+	discovered := *s
+	discovered.discoverFrom, discovered.discoverName = "", ""
+	discovered.path = path
+	return &discovered, nil
+}
+
+// discoverFile searches the given directory and its parents for a file with
+// the given name. The search stops at the first directory containing a
+// discovery marker or at the root of the filesystem.
+func discoverFile(directory, name string) (string, error) {
+	if directory == "" {
+		workingDirectory, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		directory = workingDirectory
+	}
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return "", err
+	}
+
+	for current := directory; ; {
+		candidate := filepath.Join(current, name)
+		info, err := os.Stat(candidate)
+		if err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		for _, marker := range discoveryMarkers {
+			if _, err := os.Stat(filepath.Join(current, marker)); err == nil {
+				return "", fmt.Errorf("'%s' not found between '%s' and project root '%s': %w", name, directory, current, yagcl.ErrSourceNotFound)
+			}
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", fmt.Errorf("'%s' not found in '%s' or any of its parents: %w", name, directory, yagcl.ErrSourceNotFound)
+		}
+		current = parent
+	}
+}
@@ -0,0 +1,366 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// SyntaxError is returned if the data of a source isn't a valid .env
+// document. Use errors.As to access the location.
+type SyntaxError struct {
+	// Path is the path of the file, empty for sources not reading a file.
+	Path string
+	// Line starts at 1.
+	Line int
+	// Column starts at 1 and counts characters, not bytes.
+	Column int
+	// Message describes the problem.
+	Message string
+}
+
+// Error implements error.Error.
+func (e *SyntaxError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// variable is a single definition found in a .env document.
+type variable struct {
+	key   string
+	value string
+	// line is the line the definition starts at.
+	line int
+	// path is the file the definition has been read from, if any.
+	path string
+}
+
+func newDotenvParser(path string, content []byte) *dotenvParser {
+	return &dotenvParser{
+		path:    path,
+		content: strings.TrimPrefix(string(content), "\ufeff"),
+		line:    1,
+		values:  make(map[string]string),
+	}
+}
+
+// dotenvParser holds the state of parsing a single document. Despite its name,
+// it is used for all dialects.
+type dotenvParser struct {
+	path    string
+	content string
+
+	offset int
+	line   int
+	// lineStart is the offset of the first character of the current line.
+	lineStart int
+
+	variables []variable
+	// values holds the latest value of each key, used for expansion.
+	values map[string]string
+	// preferDefinitions causes expansion to prefer previous definitions over
+	// the environment of the process, as a shell would.
+	preferDefinitions bool
+}
+
+// parse parses a .env document using the grammar of DialectDotenv, which is
+// as follows:
+//
+//   - Empty lines and lines starting with '#' are ignored.
+//   - Definitions have the form KEY=VALUE and may be prefixed with "export ".
+//     Instead of '=', ':' may be used as well. Whitespace around the key and
+//     the value is ignored.
+//   - Keys consist of letters, digits, '_' and '.'.
+//   - Unquoted values end at the end of the line or at a '#' preceded by
+//     whitespace, which starts a comment.
+//   - Single quoted values are taken literally and can't span multiple lines.
+//   - Double quoted values may span multiple lines and support the escape
+//     sequences \n, \r, \t, \", \\ and \$. Other backslashes are kept.
+//   - Unquoted and double quoted values expand ${KEY} and $KEY, looking up the
+//     environment of the process first, followed by the previous definitions.
+//     Unknown variables expand to an empty string. \$ prevents expansion.
+func (p *dotenvParser) parse() error {
+	for p.offset < len(p.content) {
+		p.skipWhitespace()
+		if p.atLineEnd() {
+			p.skipLineEnd()
+			continue
+		}
+		if p.peek() == '#' {
+			p.skipComment()
+			continue
+		}
+
+		if err := p.parseDefinition(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *dotenvParser) parseDefinition() error {
+	definition := variable{line: p.line}
+	definition.key = p.readKey()
+	// "export" is only a prefix if followed by whitespace, as it could be a
+	// key as well.
+	if definition.key == "export" && (p.peek() == ' ' || p.peek() == '\t') {
+		p.skipWhitespace()
+		definition.key = p.readKey()
+	}
+	if definition.key == "" {
+		return p.errorf("unexpected character %q, expected a key", p.peekRune())
+	}
+
+	p.skipWhitespace()
+	if p.peek() != '=' && p.peek() != ':' {
+		if p.atLineEnd() {
+			return p.errorf("missing '=' after key '%s'", definition.key)
+		}
+		return p.errorf("unexpected character %q in key '%s'", p.peekRune(), definition.key)
+	}
+	p.offset++
+	p.skipWhitespace()
+
+	var err error
+	switch p.peek() {
+	case '\'':
+		definition.value, err = p.readSingleQuoted()
+	case '"':
+		definition.value, err = p.readDoubleQuoted()
+	default:
+		definition.value, err = p.readUnquoted()
+	}
+	if err != nil {
+		return err
+	}
+
+	p.skipWhitespace()
+	if p.peek() == '#' {
+		p.skipComment()
+	} else if p.atLineEnd() {
+		p.skipLineEnd()
+	} else {
+		return p.errorf("unexpected character %q after value of key '%s'", p.peekRune(), definition.key)
+	}
+
+	p.define(definition)
+	return nil
+}
+
+func (p *dotenvParser) define(definition variable) {
+	definition.path = p.path
+	p.variables = append(p.variables, definition)
+	p.values[definition.key] = definition.value
+}
+
+func (p *dotenvParser) readKey() string {
+	start := p.offset
+	for p.offset < len(p.content) && isKeyCharacter(p.content[p.offset]) {
+		p.offset++
+	}
+	return p.content[start:p.offset]
+}
+
+func isKeyCharacter(character byte) bool {
+	return (character >= 'a' && character <= 'z') ||
+		(character >= 'A' && character <= 'Z') ||
+		(character >= '0' && character <= '9') ||
+		character == '_' || character == '.'
+}
+
+func (p *dotenvParser) readSingleQuoted() (string, error) {
+	quoteLine, quoteColumn := p.line, p.column()
+	p.offset++
+	end := strings.IndexAny(p.content[p.offset:], "'\r\n")
+	if end == -1 || p.content[p.offset+end] != '\'' {
+		return "", p.errorAt(quoteLine, quoteColumn, "unterminated single quoted value")
+	}
+	value := p.content[p.offset : p.offset+end]
+	p.offset += end + 1
+	return value, nil
+}
+
+func (p *dotenvParser) readDoubleQuoted() (string, error) {
+	quoteLine, quoteColumn := p.line, p.column()
+	p.offset++
+
+	var builder strings.Builder
+	for p.offset < len(p.content) {
+		switch character := p.content[p.offset]; character {
+		case '"':
+			p.offset++
+			return builder.String(), nil
+		case '\r', '\n':
+			// Line endings are normalized, no matter which kind is used.
+			p.skipLineEnd()
+			builder.WriteByte('\n')
+		case '\\':
+			p.offset++
+			if p.offset == len(p.content) {
+				builder.WriteByte('\\')
+				continue
+			}
+			switch escaped := p.content[p.offset]; escaped {
+			case 'n':
+				builder.WriteByte('\n')
+			case 'r':
+				builder.WriteByte('\r')
+			case 't':
+				builder.WriteByte('\t')
+			case '"', '\\', '$':
+				builder.WriteByte(escaped)
+			default:
+				builder.WriteByte('\\')
+				continue
+			}
+			p.offset++
+		case '$':
+			if err := p.expand(&builder); err != nil {
+				return "", err
+			}
+		default:
+			builder.WriteByte(character)
+			p.offset++
+		}
+	}
+	return "", p.errorAt(quoteLine, quoteColumn, "unterminated double quoted value")
+}
+
+func (p *dotenvParser) readUnquoted() (string, error) {
+	var builder strings.Builder
+	for !p.atLineEnd() {
+		character := p.content[p.offset]
+		// A comment has to be preceded by whitespace, as values such as
+		// URLs may contain '#'.
+		if character == '#' && p.offset > 0 && (p.content[p.offset-1] == ' ' || p.content[p.offset-1] == '\t') {
+			break
+		}
+
+		switch {
+		case character == '\\' && p.offset+1 < len(p.content) && p.content[p.offset+1] == '$':
+			builder.WriteByte('$')
+			p.offset += 2
+		case character == '$':
+			if err := p.expand(&builder); err != nil {
+				return "", err
+			}
+		default:
+			builder.WriteByte(character)
+			p.offset++
+		}
+	}
+	return strings.TrimRight(builder.String(), " \t"), nil
+}
+
+// expand writes the value of the variable referenced at the current '$'. If
+// no valid variable name follows, '$' is taken literally.
+func (p *dotenvParser) expand(builder *strings.Builder) error {
+	dollarColumn := p.column()
+	p.offset++
+
+	var name string
+	if p.peek() == '{' {
+		end := strings.IndexAny(p.content[p.offset:], "}\r\n")
+		if end == -1 || p.content[p.offset+end] != '}' {
+			return p.errorAt(p.line, dollarColumn, "unterminated variable reference")
+		}
+		name = p.content[p.offset+1 : p.offset+end]
+		if !isVariableName(name) {
+			return p.errorAt(p.line, dollarColumn, fmt.Sprintf("invalid variable name '%s'", name))
+		}
+		p.offset += end + 1
+	} else {
+		start := p.offset
+		for p.offset < len(p.content) && isVariableName(p.content[start:p.offset+1]) {
+			p.offset++
+		}
+		name = p.content[start:p.offset]
+		if name == "" {
+			builder.WriteByte('$')
+			return nil
+		}
+	}
+
+	if value, ok := p.values[name]; ok && p.preferDefinitions {
+		builder.WriteString(value)
+	} else if value, ok := os.LookupEnv(name); ok {
+		builder.WriteString(value)
+	} else {
+		builder.WriteString(p.values[name])
+	}
+	return nil
+}
+
+func isVariableName(name string) bool {
+	for index := 0; index < len(name); index++ {
+		character := name[index]
+		if !((character >= 'a' && character <= 'z') ||
+			(character >= 'A' && character <= 'Z') ||
+			character == '_' ||
+			(index > 0 && character >= '0' && character <= '9')) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+func (p *dotenvParser) peek() byte {
+	if p.offset < len(p.content) {
+		return p.content[p.offset]
+	}
+	return 0
+}
+
+func (p *dotenvParser) peekRune() rune {
+	character, _ := utf8.DecodeRuneInString(p.content[p.offset:])
+	return character
+}
+
+func (p *dotenvParser) atLineEnd() bool {
+	return p.offset == len(p.content) || p.content[p.offset] == '\r' || p.content[p.offset] == '\n'
+}
+
+func (p *dotenvParser) skipWhitespace() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.offset++
+	}
+}
+
+func (p *dotenvParser) skipComment() {
+	for !p.atLineEnd() {
+		p.offset++
+	}
+	p.skipLineEnd()
+}
+
+// skipLineEnd skips CR, LF or CRLF, if present.
+func (p *dotenvParser) skipLineEnd() {
+	switch p.peek() {
+	case '\r':
+		p.offset++
+		if p.peek() == '\n' {
+			p.offset++
+		}
+	case '\n':
+		p.offset++
+	default:
+		return
+	}
+	p.line++
+	p.lineStart = p.offset
+}
+
+func (p *dotenvParser) column() int {
+	return utf8.RuneCountInString(p.content[p.lineStart:p.offset]) + 1
+}
+
+func (p *dotenvParser) errorf(format string, args ...any) error {
+	return p.errorAt(p.line, p.column(), fmt.Sprintf(format, args...))
+}
+
+func (p *dotenvParser) errorAt(line, column int, message string) error {
+	return &SyntaxError{Path: p.path, Line: line, Column: column, Message: message}
+}
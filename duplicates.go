@@ -0,0 +1,69 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDuplicateKeys is thrown if StrictDuplicates is used and a key is defined
+// more than once within the same document. The error message lists all
+// duplicates.
+var ErrDuplicateKeys = errors.New("duplicate keys")
+
+// Duplicate describes a key, that has been defined more than once within the
+// same document. If a key is defined more than twice, each redefinition is
+// reported separately. Keys overridden by later sources of a yagcl pipeline
+// aren't duplicates, as these overrides are intentional.
+type Duplicate struct {
+	Key string
+	// Path is the path of the file, empty for sources not reading a file.
+	Path string
+	// PreviousLine is the line of the previous definition.
+	PreviousLine int
+	// Line is the line of the definition, that replaces the previous one.
+	Line int
+}
+
+// String formats the duplicate for logging.
+func (d Duplicate) String() string {
+	message := fmt.Sprintf("key '%s' in line %d has already been defined in line %d", d.Key, d.Line, d.PreviousLine)
+	if d.Path != "" {
+		return d.Path + ": " + message
+	}
+	return message
+}
+
+// checkDuplicates reports all keys defined more than once to the configured
+// callback and fails if strict duplicate checks are enabled.
+func (s *envSourceImpl) checkDuplicates(variables []variable) error {
+	if !s.strictDuplicates && s.onDuplicate == nil {
+		return nil
+	}
+
+	var duplicates []string
+	lines := make(map[string]int, len(variables))
+	for _, variable := range variables {
+		previousLine, defined := lines[variable.key]
+		lines[variable.key] = variable.line
+		if !defined {
+			continue
+		}
+
+		duplicate := Duplicate{
+			Key:          variable.key,
+			Path:         s.path,
+			PreviousLine: previousLine,
+			Line:         variable.line,
+		}
+		if s.onDuplicate != nil {
+			s.onDuplicate(duplicate)
+		}
+		duplicates = append(duplicates, duplicate.String())
+	}
+
+	if s.strictDuplicates && len(duplicates) > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateKeys, strings.Join(duplicates, "; "))
+	}
+	return nil
+}
@@ -1,9 +1,7 @@
 package env
 
 import (
-	"bytes"
 	"encoding"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -15,28 +13,58 @@ import (
 	"time"
 
 	"github.com/Bios-Marcel/yagcl"
-	"github.com/subosito/gotenv"
+	"github.com/Bios-Marcel/yagcl-env/internal/codec"
 )
 
 // ErrNoDataSourceSpecified is thrown if none Bytes, String, Path or Reader
 // of the EnvSourceSetupStepOne interface have been called.
-var ErrNoDataSourceSpecified = errors.New("no data source specified; call Bytes(), String(), Reader() or Path()")
+var ErrNoDataSourceSpecified = errors.New("no data source specified; call Bytes(), String(), Reader(), Path(), Paths() or Discover()")
 
 // ErrNoDataSourceSpecified is thrown if more than one of Bytes, String, Path
 // or Reader of the EnvSourceSetupStepOne interface have been called.
-var ErrMultipleDataSourcesSpecified = errors.New("more than one data source specified; only call one of Bytes(), String(), Reader() or Path()")
+var ErrMultipleDataSourcesSpecified = errors.New("more than one data source specified; only call one of Bytes(), String(), Reader(), Path(), Paths() or Discover()")
+
+// ErrUnsupportedByteEncoding is thrown if the `encoding` tag of a []byte or
+// [N]byte field contains an unknown value.
+var ErrUnsupportedByteEncoding = codec.ErrUnsupportedByteEncoding
+
+// ErrRequiredKeysMissing is thrown if fields marked via `env:"KEY,required"`
+// couldn't be found in the source. The error message lists all missing keys.
+var ErrRequiredKeysMissing = codec.ErrRequiredKeysMissing
+
+// ErrAmbiguousRequired is thrown if a field is marked via both
+// `env:"KEY,required"` and `required:"true"`. Since the former demands the
+// key to be present in the source, while the latter demands a non-zero value
+// after parsing, which may also be a default, only one of them may be used.
+var ErrAmbiguousRequired = errors.New("field is marked as required via both the env tag and the required tag")
 
 type envSourceImpl struct {
-	path    string
-	bytes   []byte
-	reader  io.Reader
-	readEnv bool
+	path         string
+	bytes        []byte
+	reader       io.Reader
+	readEnv      bool
+	patterns     []string
+	discoverFrom string
+	discoverName string
 
 	must              bool
 	loadIntoEnv       bool
+	loadPolicy        LoadPolicy
+	loadPrefixedOnly  bool
+	loadResult        *LoadResult
+	format            Format
+	dialect           Dialect
+	keyMapping        func(string) string
+	strictDuplicates  bool
+	onDuplicate       func(Duplicate)
 	prefix            string
 	keyValueConverter func(string) string
 	keyJoiner         func(string, string) string
+	report            *Report
+
+	// plans caches the decoding plans if custom key functions are used, see
+	// envSourceImpl.planCache.
+	plans *planCache
 }
 
 type EnvSourceSetupStepOne[T yagcl.Source] interface {
@@ -49,6 +77,21 @@ type EnvSourceSetupStepOne[T yagcl.Source] interface {
 	// Reader defines a reader that is accessed when YAGCL.Parse is called. IF
 	// available, io.Closer.Close() is called.
 	Reader(io.Reader) EnvSourceSetupStepTwoEnvFile[T]
+	// Paths defines multiple files, which are accessed when YAGCL.Parse is
+	// called, merged into a single source. Each entry can either be a path or
+	// a pattern as accepted by filepath.Glob. Entries are read in the given
+	// order, while the files matching the same pattern are read in lexical
+	// order. Files read later override keys defined by files read earlier.
+	// Missing files and patterns without matches are skipped, unless Must is
+	// used.
+	Paths(patterns ...string) EnvSourceSetupStepTwoEnvFile[T]
+	// Discover searches the current working directory and its parents for a
+	// file with the given name, such as ".env". The search stops at the
+	// first directory containing a go.mod file or a .git directory. The
+	// search is repeated each time YAGCL.Parse is called.
+	Discover(name string) EnvSourceSetupStepTwoEnvFile[T]
+	// DiscoverFrom is like Discover, but starts at the given directory.
+	DiscoverFrom(directory, name string) EnvSourceSetupStepTwoEnvFile[T]
 	// Env instructs the source to read directly from the environment
 	// variables.
 	Env() EnvSourceSetupStepTwoEnv[T]
@@ -58,8 +101,34 @@ type EnvSourceSetupStepTwoEnvFile[T yagcl.Source] interface {
 	EnvSourceOptionalSetup[T]
 
 	// LoadIntoEnv activates loading the unparsed data into the environment
-	// variables of the process.
+	// variables of the process. Existing variables are overwritten.
 	LoadIntoEnv() T
+	// LoadIntoEnvPolicy is like LoadIntoEnv, but defines how variables
+	// already present in the environment are treated.
+	LoadIntoEnvPolicy(LoadPolicy) T
+	// LoadPrefixedOnly restricts loading into the environment to keys
+	// starting with the configured prefix.
+	LoadPrefixedOnly() T
+	// LoadResult defines a result, that is filled with the keys that have
+	// been set, skipped or conflicted when loading into the environment.
+	LoadResult(*LoadResult) T
+	// Format defines the file format of the data source, which defaults to
+	// FormatEnv.
+	Format(Format) T
+	// Dialect defines the grammar used to parse the data source, which
+	// defaults to DialectDotenv. It only applies to FormatEnv.
+	Dialect(Dialect) T
+	// KeyMapping defines how the keys found in the data source are mapped to
+	// environment variable keys, before being looked up. By default, keys of
+	// FormatEnv are used as is, while hierarchical keys of other formats,
+	// such as "database.host", are mapped to keys such as DATABASE_HOST.
+	KeyMapping(func(string) string) T
+	// StrictDuplicates causes parsing to fail if a key is defined more than
+	// once within the data source.
+	StrictDuplicates() T
+	// OnDuplicate defines a callback, that is called for each key defined
+	// more than once within the data source.
+	OnDuplicate(func(Duplicate)) T
 	// Must declares this source as mandatory, erroring in case no data can
 	// be loaded. In case of loading directly from the environment, this
 	// will always succeed though, as the environment is always there, even
@@ -95,6 +164,9 @@ type EnvSourceOptionalSetup[T yagcl.Source] interface {
 	// The joiner could for example produce sub_field, depending. In combination
 	// with KeyValueConverter, this could then become SUB_FIELD.
 	KeyJoiner(func(string, string) string) T
+	// Report defines a report, that the provenance of each field is recorded
+	// in when parsing. The same report can be passed to multiple sources.
+	Report(*Report) T
 }
 
 // Source creates a source for environment variables of the current
@@ -103,6 +175,7 @@ func Source() EnvSourceSetupStepOne[*envSourceImpl] {
 	return &envSourceImpl{
 		keyValueConverter: defaultKeyValueConverter,
 		keyJoiner:         defaultKeyJoiner,
+		plans:             newPlanCache(),
 	}
 }
 
@@ -144,6 +217,55 @@ func (s *envSourceImpl) LoadIntoEnv() *envSourceImpl {
 	return s
 }
 
+// LoadIntoEnvPolicy implements EnvSourceSetupStepTwoEnvFile.LoadIntoEnvPolicy.
+func (s *envSourceImpl) LoadIntoEnvPolicy(policy LoadPolicy) *envSourceImpl {
+	s.loadIntoEnv = true
+	s.loadPolicy = policy
+	return s
+}
+
+// LoadPrefixedOnly implements EnvSourceSetupStepTwoEnvFile.LoadPrefixedOnly.
+func (s *envSourceImpl) LoadPrefixedOnly() *envSourceImpl {
+	s.loadPrefixedOnly = true
+	return s
+}
+
+// LoadResult implements EnvSourceSetupStepTwoEnvFile.LoadResult.
+func (s *envSourceImpl) LoadResult(result *LoadResult) *envSourceImpl {
+	s.loadResult = result
+	return s
+}
+
+// Format implements EnvSourceSetupStepTwoEnvFile.Format.
+func (s *envSourceImpl) Format(format Format) *envSourceImpl {
+	s.format = format
+	return s
+}
+
+// KeyMapping implements EnvSourceSetupStepTwoEnvFile.KeyMapping.
+func (s *envSourceImpl) KeyMapping(keyMapping func(string) string) *envSourceImpl {
+	s.keyMapping = keyMapping
+	return s
+}
+
+// Dialect implements EnvSourceSetupStepTwoEnvFile.Dialect.
+func (s *envSourceImpl) Dialect(dialect Dialect) *envSourceImpl {
+	s.dialect = dialect
+	return s
+}
+
+// StrictDuplicates implements EnvSourceSetupStepTwoEnvFile.StrictDuplicates.
+func (s *envSourceImpl) StrictDuplicates() *envSourceImpl {
+	s.strictDuplicates = true
+	return s
+}
+
+// OnDuplicate implements EnvSourceSetupStepTwoEnvFile.OnDuplicate.
+func (s *envSourceImpl) OnDuplicate(onDuplicate func(Duplicate)) *envSourceImpl {
+	s.onDuplicate = onDuplicate
+	return s
+}
+
 // Must implements EnvSourceOptionalSetup.Must.
 func (s *envSourceImpl) Must() *envSourceImpl {
 	s.must = true
@@ -159,6 +281,7 @@ func (s *envSourceImpl) Prefix(prefix string) *envSourceImpl {
 // KeyValueConverter implements EnvSourceOptionalSetup.KeyValueConverter.
 func (s *envSourceImpl) KeyValueConverter(keyValueConverter func(string) string) *envSourceImpl {
 	s.keyValueConverter = keyValueConverter
+	s.plans.reset()
 	return s
 }
 
@@ -172,6 +295,7 @@ func defaultKeyValueConverter(s string) string {
 // KeyJoiner implements EnvSourceOptionalSetup.KeyJoiner.
 func (s *envSourceImpl) KeyJoiner(keyJoiner func(string, string) string) *envSourceImpl {
 	s.keyJoiner = keyJoiner
+	s.plans.reset()
 	return s
 }
 
@@ -188,6 +312,12 @@ func defaultKeyJoiner(s1, s2 string) string {
 	return strings.Trim(s1, "_") + "_" + strings.Trim(s2, "_")
 }
 
+// Report implements EnvSourceOptionalSetup.Report.
+func (s *envSourceImpl) Report(report *Report) *envSourceImpl {
+	s.report = report
+	return s
+}
+
 // KeyTag implements Source.Key.
 func (s *envSourceImpl) KeyTag() string {
 	return "env"
@@ -195,39 +325,37 @@ func (s *envSourceImpl) KeyTag() string {
 
 type envLookup func(key string) (string, bool)
 
-func (s *envSourceImpl) load() (lookup envLookup, err error) {
-	var env gotenv.Env
+// load reads and parses the configured data source, returning all
+// definitions in order.
+func (s *envSourceImpl) load() (variables []variable, err error) {
+	if len(s.patterns) > 0 {
+		return s.loadFragments()
+	}
 
+	var content []byte
 	// We attempt to check if the source can't be found. While we only do
 	// direct file access in case a path is passed, a reader might also
 	// attempt reading from a file source, therefore we try to check that
-	// error as well. If the source has been set successfuly, we convert it
-	// into a lookup function.
+	// error as well. If the source has been read successfuly, we parse it.
 	defer func() {
 		if err != nil {
 			return
 		}
 
-		lookup = func(key string) (string, bool) {
-			val, set := env[key]
-			return val, set
-		}
-
-		if s.loadIntoEnv {
-			for key, val := range env {
-				os.Setenv(key, val)
-			}
+		variables, err = s.parseContent(content)
+		if err == nil {
+			err = s.checkDuplicates(variables)
 		}
 	}()
 
 	// Do bytes first, since it saves us the error handling code.
 	if len(s.bytes) > 0 {
-		env, err = gotenv.StrictParse(bytes.NewReader(s.bytes))
+		content = s.bytes
 		return
 	}
 
 	if s.path != "" {
-		env, err = gotenv.Read(s.path)
+		content, err = os.ReadFile(s.path)
 		return
 	}
 
@@ -236,7 +364,7 @@ func (s *envSourceImpl) load() (lookup envLookup, err error) {
 			defer closer.Close()
 		}
 
-		env, err = gotenv.StrictParse(s.reader)
+		content, err = io.ReadAll(s.reader)
 		return
 	}
 
@@ -263,6 +391,12 @@ func (s *envSourceImpl) verify() error {
 	if s.reader != nil {
 		dataSourcesCount++
 	}
+	if len(s.patterns) > 0 {
+		dataSourcesCount++
+	}
+	if s.discoverName != "" {
+		dataSourcesCount++
+	}
 
 	if dataSourcesCount == 0 {
 		return ErrNoDataSourceSpecified
@@ -284,15 +418,22 @@ func (s *envSourceImpl) verify() error {
 	return nil
 }
 
+// sourceError maps errors caused by a missing source to
+// yagcl.ErrSourceNotFound, ignoring them unless the source is mandatory.
+func (s *envSourceImpl) sourceError(err error) error {
+	if os.IsNotExist(err) || errors.Is(err, fs.ErrNotExist) {
+		err = yagcl.ErrSourceNotFound
+	}
+	if !s.must && errors.Is(err, yagcl.ErrSourceNotFound) {
+		return nil
+	}
+	return err
+}
+
 // Parse implements Source.Parse.
 func (s *envSourceImpl) Parse(parsingCompanion yagcl.ParsingCompanion, configurationStruct any) (dataLoaded bool, err error) {
 	defer func() {
-		if os.IsNotExist(err) || errors.Is(err, fs.ErrNotExist) {
-			err = yagcl.ErrSourceNotFound
-		}
-		if !s.must && errors.Is(err, yagcl.ErrSourceNotFound) {
-			err = nil
-		}
+		err = s.sourceError(err)
 		if err != nil {
 			dataLoaded = false
 		}
@@ -301,135 +442,230 @@ func (s *envSourceImpl) Parse(parsingCompanion yagcl.ParsingCompanion, configura
 	if err = s.verify(); err != nil {
 		return
 	}
+	if s.discoverName != "" {
+		var discovered *envSourceImpl
+		if discovered, err = s.resolve(); err != nil {
+			return
+		}
+		return discovered.Parse(parsingCompanion, configurationStruct)
+	}
 
-	var lookup envLookup
+	var (
+		lookup      envLookup
+		values      map[string]string
+		definitions map[string]variable
+	)
 	if s.readEnv {
 		lookup = os.LookupEnv
 	} else {
-		lookup, err = s.load()
+		var variables []variable
+		variables, err = s.load()
 		if err != nil {
 			return
 		}
+		values, definitions = indexVariables(variables)
+		lookup = func(key string) (string, bool) {
+			val, set := values[key]
+			return val, set
+		}
 	}
 
 	// FIXME For now we always say we've loaded something, this should change
 	// at some point, using some kind of "was at least one variable loaded"
 	// check.
 	dataLoaded = true
-	err = s.parse(parsingCompanion, lookup, s.prefix, reflect.Indirect(reflect.ValueOf(configurationStruct)))
+	structValue := reflect.Indirect(reflect.ValueOf(configurationStruct))
+	plan, err := s.plan(parsingCompanion, structValue.Type())
+	if err != nil {
+		return
+	}
+	state := &parseState{lookup: lookup, definitions: definitions}
+	if err = s.parse(state, plan, structValue); err != nil {
+		return
+	}
+	if len(state.missingKeys) > 0 {
+		err = fmt.Errorf("keys [%s]: %w", strings.Join(state.missingKeys, ", "), ErrRequiredKeysMissing)
+		return
+	}
+	if len(state.violations) > 0 {
+		err = state.violations
+		return
+	}
+
+	// Loading into the environment only happens after parsing succeeded,
+	// so that invalid data doesn't leave the environment half modified.
+	if s.loadIntoEnv {
+		err = s.loadIntoEnvironment(values)
+	}
 	return
 }
 
-func (s *envSourceImpl) parse(parsingCompanion yagcl.ParsingCompanion, lookup envLookup, envPrefix string, structValue reflect.Value) error {
-	structType := structValue.Type()
-	for i := 0; i < structValue.NumField(); i++ {
-		structField := structType.Field(i)
-		if !parsingCompanion.IncludeField(structField) {
-			continue
-		}
+// indexVariables returns the value and the last definition of each key.
+func indexVariables(variables []variable) (map[string]string, map[string]variable) {
+	values := make(map[string]string, len(variables))
+	definitions := make(map[string]variable, len(variables))
+	for _, variable := range variables {
+		values[variable.key] = variable.value
+		definitions[variable.key] = variable
+	}
+	return values, definitions
+}
 
-		envKey, errExtractKey := s.extractEnvKey(parsingCompanion, structField)
-		if errExtractKey != nil {
-			return errExtractKey
-		}
-		joinedEnvKey := s.keyJoiner(envPrefix, envKey)
-		envValue, set := lookup(joinedEnvKey)
-		if !set {
-			// Since we handle pointers and structs differently, we must not do early exists / errors in these cases.
-			if structField.Type.Kind() != reflect.Struct && structField.Type.Kind() != reflect.Pointer {
-				continue
+// parseState holds everything required during a single call to Parse, which
+// isn't part of the source configuration itself.
+type parseState struct {
+	lookup envLookup
+
+	// violations collects all validation errors, so that we can report them
+	// all at once, instead of failing on the first one.
+	violations ValidationErrors
+	// missingKeys collects the joined keys of all fields that are required to
+	// be present in this source, but couldn't be found.
+	missingKeys []string
+	// definitions holds the last definition of each key, if the source isn't
+	// the environment.
+	definitions map[string]variable
+}
+
+func (s *envSourceImpl) parse(state *parseState, plan *structPlan, structValue reflect.Value) error {
+	for _, field := range plan.fields {
+		envValue, set := state.lookup(field.joinedEnvKey)
+		found, defaulted := set, false
+		value := structValue.Field(field.index)
+		// Nested structs don't have a value of their own, so we must not do
+		// early exits / errors in these cases, but recurse instead.
+		if set || field.nested != nil {
+			if errParse := s.parseField(state, field, envValue, value); errParse != nil {
+				return errParse
+			}
+		} else {
+			if field.options.required {
+				state.missingKeys = append(state.missingKeys, field.joinedEnvKey)
 			}
-		}
 
-		value := structValue.Field(i)
-
-		// For pointers, we require the non-pointer type underneath.
-		underlyingType := extractNonPointerFieldType(value.Type())
-
-		// In this section we check whether custom unmarshallers are present.
-		// Types with a custom unmarshaller have to be checked first before
-		// attempting to parse them using default behaviour, as the behaviour
-		// might differ from std/json otherwise.
-
-		// Technically this check isn't required, as we already filter out
-		// unexported fields. However, I am unsure whether this behaviour is set
-		// in stone, as it hasn't been documented properly.
-		// https://stackoverflow.com/questions/50279840/when-is-go-reflect-caninterface-false
-		if value.CanInterface() {
-			// Here we try to find the deepest pointer type. As something
-			// like ***type doesn't allow calling `TextUnmarshal` and a value
-			// type doesn't allow it either. If we get a value type instead of
-			// a pointer, we manually wrap it.
-			var target reflect.Value
-			if deepestPotentialPointer := extractDeepestPotentialPointer(value); deepestPotentialPointer.Kind() == reflect.Pointer {
-				if deepestPotentialPointer.IsNil() {
-					target = reflect.New(underlyingType)
-				} else {
-					target = deepestPotentialPointer
+			// Defaults defined via tag are treated as if they were the
+			// value found in the source. However, we don't want to overwrite
+			// values set by previous sources or manually set defaults.
+			if field.hasDefault && value.IsZero() {
+				if errParse := s.parseField(state, field, field.defaultValue, value); errParse != nil {
+					return fmt.Errorf("invalid default value for field '%s': %w", field.structField.Name, errParse)
 				}
-			} else {
-				target = reflect.New(underlyingType)
-				// Preserve potential defaults set in non-pointer value.
-				target.Elem().Set(value)
+				set, defaulted = true, true
 			}
+		}
 
-			if u, ok := target.Interface().(encoding.TextUnmarshaler); ok {
-				if err := u.UnmarshalText([]byte(envValue)); err != nil {
-					return fmt.Errorf("value '%s' isn't parsable as an '%s' for field '%s'; %s: %w", envValue, underlyingType.String(), structField.Name, err, yagcl.ErrParseValue)
-				}
+		state.violations = append(state.violations, field.rules.validate(field.joinedEnvKey, value, set)...)
 
-				value.Set(convertValueToPointerIfRequired(value, reflect.Indirect(target)))
-				// We are done with this field and don't need to fall back to
-				// the default parsing logic.
-				continue
-			}
+		if s.report != nil && field.nested == nil {
+			s.recordProvenance(state, field, value, found, defaulted)
 		}
+	}
 
-		parsed, errParseValue := parseValue(structField.Name, structField.Type, envValue)
-		if errParseValue != nil {
-			if errParseValue != errEmbeddedStructDetected {
-				return errParseValue
-			}
+	return nil
+}
 
-			// If we have a non-pointer struct, it may contain default
-			// values, which we want to preserve by not creating a new
-			// instance of the struct.
-			if deepestPotentialPointer := extractDeepestPotentialPointer(value); deepestPotentialPointer.Kind() != reflect.Pointer {
-				if errParse := s.parse(parsingCompanion, lookup, joinedEnvKey, deepestPotentialPointer); errParse != nil {
-					return errParse
-				}
-				continue
-			} else
-			// Non-nil Pointervalue, therefore we gotta use the existing
-			// value in order to preserve potentially existing defaults.
-			if !deepestPotentialPointer.IsZero() {
-				if errParse := s.parse(parsingCompanion, lookup, joinedEnvKey, deepestPotentialPointer.Elem()); errParse != nil {
-					return errParse
-				}
-				continue
-			}
+// parseField parses the given envValue into the value of the given field. If
+// the field is a struct, we recurse using the nested plan.
+func (s *envSourceImpl) parseField(
+	state *parseState,
+	field *fieldPlan,
+	envValue string,
+	value reflect.Value,
+) error {
+	if field.nested != nil {
+		// If we have a non-pointer struct, it may contain default
+		// values, which we want to preserve by not creating a new
+		// instance of the struct.
+		if deepestPotentialPointer := extractDeepestPotentialPointer(value); deepestPotentialPointer.Kind() != reflect.Pointer {
+			return s.parse(state, field.nested, deepestPotentialPointer)
+		} else
+		// Non-nil Pointervalue, therefore we gotta use the existing
+		// value in order to preserve potentially existing defaults.
+		if !deepestPotentialPointer.IsZero() {
+			return s.parse(state, field.nested, deepestPotentialPointer.Elem())
+		}
 
-			underlyingType := extractNonPointerFieldType(structField.Type.Elem())
-			newStruct := reflect.Indirect(reflect.New(underlyingType))
-			if errParse := s.parse(parsingCompanion, lookup, joinedEnvKey, newStruct); errParse != nil {
-				return errParse
+		newStruct := reflect.Indirect(reflect.New(field.underlyingType))
+		if errParse := s.parse(state, field.nested, newStruct); errParse != nil {
+			return errParse
+		}
+		return setParsedValue(field, value, newStruct)
+	}
+
+	// Types with a custom unmarshaller have to be checked first before
+	// attempting to parse them using default behaviour, as the behaviour
+	// might differ from std/json otherwise.
+	if field.unmarshalsText {
+		// Here we try to find the deepest pointer type. As something
+		// like ***type doesn't allow calling `TextUnmarshal` and a value
+		// type doesn't allow it either. If we get a value type instead of
+		// a pointer, we manually wrap it.
+		var target reflect.Value
+		if deepestPotentialPointer := extractDeepestPotentialPointer(value); deepestPotentialPointer.Kind() == reflect.Pointer {
+			if deepestPotentialPointer.IsNil() {
+				target = reflect.New(field.underlyingType)
+			} else {
+				target = deepestPotentialPointer
 			}
-			parsed = newStruct
+		} else {
+			target = reflect.New(field.underlyingType)
+			// Preserve potential defaults set in non-pointer value.
+			target.Elem().Set(value)
 		}
 
-		if parsed.IsZero() {
-			continue
+		if err := target.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(envValue)); err != nil {
+			return fmt.Errorf("value '%s' isn't parsable as an '%s' for field '%s'; %s: %w", envValue, field.underlyingType.String(), field.structField.Name, err, yagcl.ErrParseValue)
 		}
 
-		// Make sure that we have the correct alias type if necessary.
-		parsed = parsed.Convert(underlyingType)
-		parsed = convertValueToPointerIfRequired(value, parsed)
-		value.Set(parsed)
+		value.Set(convertValueToPointerIfRequired(value, reflect.Indirect(target)))
+		// We are done with this field and don't need to fall back to
+		// the default parsing logic.
+		return nil
 	}
 
+	parsed, errParseValue := parseValue(field.structField.Name, field.structField.Type, field.byteEncoding, envValue)
+	if errParseValue != nil {
+		// Nested structs are handled via their plan, so structs can only
+		// be detected here if they are part of a slice, array or map.
+		if errParseValue == errEmbeddedStructDetected {
+			return fmt.Errorf("field '%s' has unsupported type '%s': %w", field.structField.Name, field.structField.Type.String(), yagcl.ErrUnsupportedFieldType)
+		}
+		return errParseValue
+	}
+	return setParsedValue(field, value, parsed)
+}
+
+// setParsedValue assigns the parsed value to the field, creating pointers
+// and converting to alias types where necessary. Zero values are ignored.
+func setParsedValue(field *fieldPlan, value reflect.Value, parsed reflect.Value) error {
+	if parsed.IsZero() {
+		return nil
+	}
+
+	// Make sure that we have the correct alias type if necessary.
+	parsed = parsed.Convert(field.underlyingType)
+	parsed = convertValueToPointerIfRequired(value, parsed)
+	value.Set(parsed)
+
 	return nil
 }
 
+// defaultTagName is the tag used for defining a default value, which is used
+// if a key isn't present. The value is parsed the same way as the values
+// found in the source.
+const defaultTagName = "default"
+
+// isNestedStruct checks whether the type is a struct or a pointer to a struct,
+// that doesn't implement encoding.TextUnmarshaler and therefore doesn't have
+// a value of its own.
+func isNestedStruct(fieldType reflect.Type) bool {
+	underlyingType := extractNonPointerFieldType(fieldType)
+	return underlyingType.Kind() == reflect.Struct &&
+		!reflect.PointerTo(underlyingType).Implements(textUnmarshalerType)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
 // errEmbeddedStructDetected is abused internally to detect that we need to
 // recurse. This error should never reach the outer world.
 var errEmbeddedStructDetected = errors.New("embedded struct detected")
@@ -458,22 +694,47 @@ func convertValueToPointerIfRequired(targetValue reflect.Value, newValue reflect
 	return pointers[0]
 }
 
-func (s *envSourceImpl) extractEnvKey(parsingCompanion yagcl.ParsingCompanion, structField reflect.StructField) (string, error) {
-	// Custom tag
-	if key := structField.Tag.Get(s.KeyTag()); key != "" {
-		return key, nil
+// envTagOptions are the options that can be appended to the key in the
+// source specific tag, for example `env:"KEY,required"`.
+type envTagOptions struct {
+	// required declares that the key must be present in this source.
+	required bool
+}
+
+func (s *envSourceImpl) extractEnvKey(parsingCompanion yagcl.ParsingCompanion, structField reflect.StructField) (string, envTagOptions, error) {
+	var options envTagOptions
+	// Custom tag, which may contain options. The key itself may be omitted,
+	// in order to only specify options.
+	key, rawOptions, _ := strings.Cut(structField.Tag.Get(s.KeyTag()), ",")
+	for _, option := range strings.Split(rawOptions, ",") {
+		switch strings.TrimSpace(option) {
+		case "":
+		case "required":
+			options.required = true
+		default:
+			return "", options, fmt.Errorf("unknown option '%s' in tag '%s' of field '%s'", option, s.KeyTag(), structField.Name)
+		}
+	}
+	if key != "" {
+		return key, options, nil
 	}
 
 	// Fallback tag
 	if key := parsingCompanion.ExtractFieldKey(structField); key != "" {
-		return s.keyValueConverter(key), nil
+		return s.keyValueConverter(key), options, nil
 	}
 
 	// No tag found
-	return "", fmt.Errorf("neither tag '%s' nor the standard tag '%s' have been set for field '%s': %w", s.KeyTag(), yagcl.DefaultKeyTagName, structField.Name, yagcl.ErrExportedFieldMissingKey)
+	return "", options, fmt.Errorf("neither tag '%s' nor the standard tag '%s' have been set for field '%s': %w", s.KeyTag(), yagcl.DefaultKeyTagName, structField.Name, yagcl.ErrExportedFieldMissingKey)
 }
 
-func parseValue(fieldName string, fieldType reflect.Type, envValue string) (reflect.Value, error) {
+func parseValue(fieldName string, fieldType reflect.Type, byteEncoding string, envValue string) (reflect.Value, error) {
+	// Binary data is represented as []byte or [N]byte with an `encoding`
+	// tag. Without the tag, it is treated as a list of numbers.
+	if isBinary(fieldType, byteEncoding) {
+		return parseBytes(fieldName, fieldType, byteEncoding, envValue)
+	}
+
 	switch fieldType.Kind() {
 	case reflect.String:
 		{
@@ -514,11 +775,10 @@ func parseValue(fieldName string, fieldType reflect.Type, envValue string) (refl
 		}
 	case reflect.Float32, reflect.Float64:
 		{
-			// We use the stdlib json encoder here, since there seems to be
-			// special behaviour.
-			var value float64
-			if errParse := json.Unmarshal([]byte(envValue), &value); errParse != nil {
-				return reflect.Value{}, fmt.Errorf("value '%s' isn't parsable as an '%s' for field '%s': %w", envValue, fieldType.String(), fieldName, yagcl.ErrParseValue)
+			// The syntax matches encoding/json, which used to be used here.
+			value, errParse := codec.ParseFloat(fieldName, fieldType.String(), envValue)
+			if errParse != nil {
+				return reflect.Value{}, errParse
 			}
 			return reflect.ValueOf(value).Convert(fieldType), nil
 		}
@@ -543,7 +803,7 @@ func parseValue(fieldName string, fieldType reflect.Type, envValue string) (refl
 			if nonPointerFieldType.Kind() == reflect.Struct {
 				return reflect.Value{}, errEmbeddedStructDetected
 			}
-			return parseValue(fieldName, extractNonPointerFieldType(fieldType), envValue)
+			return parseValue(fieldName, extractNonPointerFieldType(fieldType), byteEncoding, envValue)
 		}
 	case reflect.Map:
 		{
@@ -553,17 +813,17 @@ func parseValue(fieldName string, fieldType reflect.Type, envValue string) (refl
 			targetMap := reflect.MakeMapWithSize(fieldType, len(rawEntries))
 			for index, entry := range rawEntries {
 				keyValue := splitString(entry, '=')
-				if len(keyValue) == 1 {
+				if len(keyValue) < 2 {
 					return reflect.Value{}, fmt.Errorf("field '%s' contains possibly misformatted value at index %d ('%s'); no unescaped '=' was found to separate key from value: %w", fieldName, index, entry, yagcl.ErrParseValue)
 				}
 				if len(keyValue) > 2 {
 					return reflect.Value{}, fmt.Errorf("field '%s' contains possibly misformatted value at index %d ('%s'); more than one unescaped '=' has been found: %w", fieldName, index, entry, yagcl.ErrParseValue)
 				}
-				parsedKey, errParseKey := parseValue(fieldName, fieldType.Key(), keyValue[0])
+				parsedKey, errParseKey := parseValue(fieldName, fieldType.Key(), byteEncoding, keyValue[0])
 				if errParseKey != nil {
 					return reflect.Value{}, fmt.Errorf("field '%s' contained unparsable key '%s': %w", fieldName, keyValue[0], yagcl.ErrParseValue)
 				}
-				parsedValue, errParseValue := parseValue(fieldName, fieldType.Elem(), keyValue[1])
+				parsedValue, errParseValue := parseValue(fieldName, fieldType.Elem(), byteEncoding, keyValue[1])
 				if errParseValue != nil {
 					return reflect.Value{}, fmt.Errorf("field '%s' contained unparsable value '%s': %w", fieldName, keyValue[1], yagcl.ErrParseValue)
 				}
@@ -580,7 +840,7 @@ func parseValue(fieldName string, fieldType reflect.Type, envValue string) (refl
 
 			arrayRawValues := splitString(envValue, ',')
 			targetArray := reflect.MakeSlice(fieldType, len(arrayRawValues), len(arrayRawValues))
-			if err := parseIntoArray(fieldName, fieldType, targetArray, arrayRawValues); err != nil {
+			if err := parseIntoArray(fieldName, fieldType, byteEncoding, targetArray, arrayRawValues); err != nil {
 				// Wrapping ErrParseValue isn't necessary, as this internally
 				// calls parseValue, which should already take care of that.
 				return reflect.Value{}, err
@@ -602,7 +862,7 @@ func parseValue(fieldName string, fieldType reflect.Type, envValue string) (refl
 			if targetArray.Len() != len(arrayRawValues) {
 				return reflect.Value{}, fmt.Errorf("value specified for field '%s' is an array of incorrect length, expected length %d, but got %d: %w", fieldName, targetArray.Len(), len(arrayRawValues), yagcl.ErrParseValue)
 			}
-			if err := parseIntoArray(fieldName, fieldType, targetArray, arrayRawValues); err != nil {
+			if err := parseIntoArray(fieldName, fieldType, byteEncoding, targetArray, arrayRawValues); err != nil {
 				// Wrapping ErrParseValue isn't necessary, as this internally
 				// calls parseValue, which should already take care of that.
 				return reflect.Value{}, err
@@ -622,37 +882,104 @@ func parseValue(fieldName string, fieldType reflect.Type, envValue string) (refl
 	}
 }
 
+// byteEncodingTagName is the tag used to define how []byte and [N]byte fields
+// are decoded. See the ByteEncoding* constants for possible values. Without
+// the tag, such fields are lists of numbers, just like any other slice.
+const byteEncodingTagName = "encoding"
+
+const (
+	// ByteEncodingRaw takes the bytes of the value as is. This is used if the
+	// `encoding` tag is present, but empty.
+	ByteEncodingRaw = codec.ByteEncodingRaw
+	// ByteEncodingBase64 decodes the value using standard, padded base64.
+	ByteEncodingBase64 = codec.ByteEncodingBase64
+	// ByteEncodingBase64URL decodes the value using URL-safe base64. Padding
+	// is optional.
+	ByteEncodingBase64URL = codec.ByteEncodingBase64URL
+	// ByteEncodingHex decodes the value as hexadecimal string.
+	ByteEncodingHex = codec.ByteEncodingHex
+)
+
+var byteType = reflect.TypeOf(byte(0))
+
+// byteEncodingOf returns the encoding defined via the `encoding` tag of the
+// given field, or an empty string if the tag isn't present.
+func byteEncodingOf(structField reflect.StructField) string {
+	byteEncoding, ok := structField.Tag.Lookup(byteEncodingTagName)
+	if ok && byteEncoding == "" {
+		return ByteEncodingRaw
+	}
+	return byteEncoding
+}
+
+// isBinary checks whether the type is []byte or [N]byte and an encoding has
+// been defined. Note that types with a named element type, such as
+// `[]myUint8` are treated like any other slice.
+func isBinary(fieldType reflect.Type, byteEncoding string) bool {
+	return byteEncoding != "" &&
+		(fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) &&
+		fieldType.Elem() == byteType
+}
+
+func parseBytes(fieldName string, fieldType reflect.Type, byteEncoding string, envValue string) (reflect.Value, error) {
+	if fieldType.Kind() == reflect.Slice {
+		decoded, err := codec.DecodeField(fieldName, byteEncoding, envValue)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(decoded).Convert(fieldType), nil
+	}
+
+	targetArray := reflect.Indirect(reflect.New(fieldType))
+	if err := codec.DecodeArray(fieldName, byteEncoding, envValue, targetArray.Slice(0, targetArray.Len()).Bytes()); err != nil {
+		return reflect.Value{}, err
+	}
+	return targetArray, nil
+}
+
 // splitString splits the given "literal" at each "splitChar" found.
 // Additionally it allows you to escape the "splitChar" by using "\", which
 // on the other hand can be escaped the same way.
 func splitString(literal string, splitChar rune) []string {
-	var values []string
-	var buffer []rune
-	var escapeNext bool
-	maxIndex := len(literal) - 1
-	for index, character := range literal {
-		if index == maxIndex {
-			if character != splitChar || escapeNext {
-				buffer = append(buffer, character)
-			}
-			values = append(values, string(buffer))
-			break
-		}
-
-		escape := escapeNext
-		escapeNext = false
+	return codec.Split(literal, splitChar)
+}
 
-		if character == splitChar && !escape {
-			values = append(values, string(buffer))
-			buffer = buffer[:0]
-		} else if character == '\\' && !escape {
-			escapeNext = true
+// escapeString is the counterpart to splitString, escaping "\" and all given
+// reserved characters, so that splitString will return the literal as is.
+func escapeString(literal string, reservedChars ...rune) string {
+	var builder strings.Builder
+	builder.Grow(len(literal))
+	for _, character := range literal {
+		if character == '\\' {
+			builder.WriteRune('\\')
 		} else {
-			buffer = append(buffer, character)
+			for _, reservedChar := range reservedChars {
+				if character == reservedChar {
+					builder.WriteRune('\\')
+					break
+				}
+			}
 		}
+		builder.WriteRune(character)
 	}
+	return builder.String()
+}
 
-	return values
+// joinString is the counterpart to splitString, escaping and joining the
+// given values. Since a trailing "splitChar" only terminates the last value,
+// another one is added if the last value is empty.
+func joinString(values []string, splitChar rune) string {
+	var builder strings.Builder
+	for index, value := range values {
+		if index > 0 {
+			builder.WriteRune(splitChar)
+		}
+		builder.WriteString(escapeString(value, splitChar))
+	}
+	if len(values) > 0 && values[len(values)-1] == "" {
+		builder.WriteRune(splitChar)
+	}
+	return builder.String()
 }
 
 func isSliceTypeSupported(sliceType reflect.Type) bool {
@@ -672,9 +999,9 @@ func isSliceTypeSupported(sliceType reflect.Type) bool {
 	return true
 }
 
-func parseIntoArray(fieldName string, fieldType reflect.Type, targetArray reflect.Value, arrayRawValues []string) error {
+func parseIntoArray(fieldName string, fieldType reflect.Type, byteEncoding string, targetArray reflect.Value, arrayRawValues []string) error {
 	for index, rawValue := range arrayRawValues {
-		parsedValue, err := parseValue(fieldName, fieldType.Elem(), rawValue)
+		parsedValue, err := parseValue(fieldName, fieldType.Elem(), byteEncoding, rawValue)
 		if err != nil {
 			return err
 		}
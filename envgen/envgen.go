@@ -0,0 +1,224 @@
+// Package envgen contains the support code for parsers generated via
+// `yagcl-env parser`. The value syntax is shared with the reflection based
+// parser, so that both behave the same. The functions aren't meant to be
+// called directly.
+//
+// The package doesn't use reflection, so that generated parsers can be used
+// in environments with limited reflection support, such as TinyGo.
+package envgen
+
+import (
+	"encoding"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/Bios-Marcel/yagcl-env/internal/codec"
+)
+
+// ErrUnsupportedByteEncoding is thrown if the `encoding` tag of a []byte or
+// [N]byte field contains an unknown value.
+var ErrUnsupportedByteEncoding = codec.ErrUnsupportedByteEncoding
+
+// ErrRequiredKeysMissing is thrown if fields marked via `env:"KEY,required"`
+// couldn't be found in the source. The error message lists all missing keys.
+var ErrRequiredKeysMissing = codec.ErrRequiredKeysMissing
+
+// Byte encodings supported by DecodeBytes.
+const (
+	ByteEncodingRaw       = codec.ByteEncodingRaw
+	ByteEncodingBase64    = codec.ByteEncodingBase64
+	ByteEncodingBase64URL = codec.ByteEncodingBase64URL
+	ByteEncodingHex       = codec.ByteEncodingHex
+)
+
+// Split splits the given "literal" at each "splitChar" found, allowing it
+// to be escaped using "".
+func Split(literal string, splitChar rune) []string {
+	return codec.Split(literal, splitChar)
+}
+
+// DecodeBytes decodes the given value using the given encoding. An empty
+// encoding equals ByteEncodingRaw.
+func DecodeBytes(byteEncoding string, value string) ([]byte, error) {
+	return codec.DecodeBytes(byteEncoding, value)
+}
+
+// ParseString assigns the value to the given string type.
+func ParseString[T ~string](value string, out *T) error {
+	*out = T(value)
+	return nil
+}
+
+// ParseInt parses a signed integer, making sure it fits into T.
+func ParseInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](fieldName, typeName, value string, out *T) error {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || int64(T(parsed)) != parsed {
+		return fmt.Errorf("value '%s' isn't parsable as an '%s' for field '%s': %w", value, typeName, fieldName, yagcl.ErrParseValue)
+	}
+	*out = T(parsed)
+	return nil
+}
+
+// ParseUint parses an unsigned integer, making sure it fits into T.
+func ParseUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64](fieldName, typeName, value string, out *T) error {
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || uint64(T(parsed)) != parsed {
+		return fmt.Errorf("value '%s' isn't parsable as an '%s' for field '%s': %w", value, typeName, fieldName, yagcl.ErrParseValue)
+	}
+	*out = T(parsed)
+	return nil
+}
+
+// ParseFloat parses a float, accepting the same syntax as encoding/json.
+func ParseFloat[T ~float32 | ~float64](fieldName, typeName, value string, out *T) error {
+	parsed, err := codec.ParseFloat(fieldName, typeName, value)
+	if err != nil {
+		return err
+	}
+	*out = T(parsed)
+	return nil
+}
+
+// ParseBool parses "true" or "false", ignoring the case.
+func ParseBool[T ~bool](fieldName, typeName, value string, out *T) error {
+	boolValue := strings.EqualFold(value, "true")
+	// Instead of assuming everything != true equals false, we assume
+	// that the value is unintentionally wrong and return an error.
+	if !boolValue && !strings.EqualFold(value, "false") {
+		return fmt.Errorf("value '%s' isn't parsable as a '%s' for field '%s': %w", value, typeName, fieldName, yagcl.ErrParseValue)
+	}
+	*out = T(boolValue)
+	return nil
+}
+
+// ParseDuration parses a duration using time.ParseDuration.
+func ParseDuration(fieldName, value string, out *time.Duration) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("value '%s' isn't parsable as an 'time.Duration' for field '%s': %w", value, fieldName, yagcl.ErrParseValue)
+	}
+	*out = parsed
+	return nil
+}
+
+// ParseBytes decodes binary data into a byte slice.
+func ParseBytes[T ~[]byte](fieldName, byteEncoding, value string, out *T) error {
+	decoded, err := codec.DecodeField(fieldName, byteEncoding, value)
+	if err != nil {
+		return err
+	}
+	*out = T(decoded)
+	return nil
+}
+
+// ParseByteArray decodes binary data into the given slice of a byte array,
+// requiring the decoded data to have the exact same length.
+func ParseByteArray(fieldName, byteEncoding, value string, out []byte) error {
+	return codec.DecodeArray(fieldName, byteEncoding, value, out)
+}
+
+// UnmarshalText calls the given encoding.TextUnmarshaler, wrapping errors.
+func UnmarshalText(fieldName, typeName, value string, target encoding.TextUnmarshaler) error {
+	if err := target.UnmarshalText([]byte(value)); err != nil {
+		return fmt.Errorf("value '%s' isn't parsable as an '%s' for field '%s'; %s: %w", value, typeName, fieldName, err, yagcl.ErrParseValue)
+	}
+	return nil
+}
+
+// CheckArrayLength makes sure that the amount of values matches the length
+// of the array.
+func CheckArrayLength(fieldName string, expected, actual int) error {
+	if expected != actual {
+		return fmt.Errorf("value specified for field '%s' is an array of incorrect length, expected length %d, but got %d: %w", fieldName, expected, actual, yagcl.ErrParseValue)
+	}
+	return nil
+}
+
+// SplitMapEntry splits a map entry into its raw key and value.
+func SplitMapEntry(fieldName string, index int, entry string) (string, string, error) {
+	keyValue := Split(entry, '=')
+	if len(keyValue) < 2 {
+		return "", "", fmt.Errorf("field '%s' contains possibly misformatted value at index %d ('%s'); no unescaped '=' was found to separate key from value: %w", fieldName, index, entry, yagcl.ErrParseValue)
+	}
+	if len(keyValue) > 2 {
+		return "", "", fmt.Errorf("field '%s' contains possibly misformatted value at index %d ('%s'); more than one unescaped '=' has been found: %w", fieldName, index, entry, yagcl.ErrParseValue)
+	}
+	return keyValue[0], keyValue[1], nil
+}
+
+// InvalidMapKey creates the error returned for unparsable map keys.
+func InvalidMapKey(fieldName, rawKey string) error {
+	return fmt.Errorf("field '%s' contained unparsable key '%s': %w", fieldName, rawKey, yagcl.ErrParseValue)
+}
+
+// InvalidMapValue creates the error returned for unparsable map values.
+func InvalidMapValue(fieldName, rawValue string) error {
+	return fmt.Errorf("field '%s' contained unparsable value '%s': %w", fieldName, rawValue, yagcl.ErrParseValue)
+}
+
+// InvalidDefault wraps errors caused by parsing the `default` tag.
+func InvalidDefault(fieldName string, err error) error {
+	return fmt.Errorf("invalid default value for field '%s': %w", fieldName, err)
+}
+
+// MissingKeys returns ErrRequiredKeysMissing listing the given keys, or nil
+// if no keys are missing.
+func MissingKeys(keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return fmt.Errorf("keys [%s]: %w", strings.Join(keys, ", "), ErrRequiredKeysMissing)
+}
+
+// The following functions allow generated code to work with values without
+// having to name their types, which might not even be possible, for example
+// for anonymous structs.
+
+// ZeroValue returns the zero value of the type of the given value.
+func ZeroValue[T any](T) T {
+	var zero T
+	return zero
+}
+
+// ZeroElem returns the zero value of the type the given pointer points to.
+func ZeroElem[T any](*T) T {
+	var zero T
+	return zero
+}
+
+// New allocates a new value of the type the given pointer points to.
+func New[T any](*T) *T {
+	return new(T)
+}
+
+// Pointer returns a pointer to a copy of the given value.
+func Pointer[T any](value T) *T {
+	return &value
+}
+
+// IsZero checks whether the given value is the zero value of its type.
+func IsZero[T comparable](value T) bool {
+	var zero T
+	return value == zero
+}
+
+// MakeSlice creates a slice of the same type as the given slice.
+func MakeSlice[S ~[]E, E any](_ S, length int) S {
+	return make(S, length)
+}
+
+// MakeMap creates a map of the same type as the given map.
+func MakeMap[M ~map[K]V, K comparable, V any](_ M, size int) M {
+	return make(M, size)
+}
+
+// MapZero returns the zero values of the key and value types of the given
+// map.
+func MapZero[M ~map[K]V, K comparable, V any](M) (K, V) {
+	var key K
+	var value V
+	return key, value
+}
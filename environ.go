@@ -0,0 +1,145 @@
+package env
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// EnvironPrecedence defines which value wins, if a key is both part of the
+// configuration and of the environment passed to WithMergedEnviron.
+type EnvironPrecedence int
+
+const (
+	// PreferConfiguration replaces variables of the environment with the
+	// values of the configuration.
+	PreferConfiguration EnvironPrecedence = iota
+	// PreferEnviron keeps variables of the environment, dropping the values
+	// of the configuration.
+	PreferEnviron
+)
+
+// Environ turns the given configuration struct into "KEY=VALUE" entries, as
+// used by os.Environ and exec.Cmd.Env. The keys and values are built the same
+// way Marshal builds them, but values are never quoted. Nil pointers are
+// omitted.
+//
+// WithMergedEnviron merges the entries with another environment, usually
+// os.Environ, while WithPrefixFilter and WithFieldAllowlist restrict the
+// entries that are passed on.
+//
+//	cmd := exec.Command("migrate")
+//	cmd.Env, err = env.Environ(cfg,
+//		env.WithPrefix("APP"),
+//		env.WithMergedEnviron(os.Environ(), env.PreferConfiguration),
+//		env.WithFieldAllowlist("Database"))
+func Environ(configurationStruct any, opts ...Option) ([]string, error) {
+	o := newOptions(opts)
+
+	structValue := reflect.Indirect(reflect.ValueOf(configurationStruct))
+	if structValue.Kind() != reflect.Struct {
+		return nil, yagcl.ErrInvalidConfiguraionPointer
+	}
+
+	var (
+		entries []string
+		keys    = make(map[string]bool)
+	)
+	err := o.walk(structValue, func(field walkedField) error {
+		if field.absent || !o.environ.allowsField(field.path) || !o.environ.allowsKey(field.joinedEnvKey) {
+			return nil
+		}
+
+		value, err := o.formatField(field)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return nil
+		}
+
+		entries = append(entries, field.joinedEnvKey+"="+*value)
+		keys[field.joinedEnvKey] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if o.environ.base == nil {
+		return entries, nil
+	}
+
+	merged := make([]string, 0, len(o.environ.base)+len(entries))
+	for _, entry := range o.environ.base {
+		key := environKey(entry)
+		if !o.environ.allowsKey(key) {
+			continue
+		}
+		if keys[key] {
+			if o.environ.precedence == PreferConfiguration {
+				continue
+			}
+			// The variable is kept, so the configuration value has to be
+			// dropped.
+			delete(keys, key)
+		}
+		merged = append(merged, entry)
+	}
+	for _, entry := range entries {
+		if keys[environKey(entry)] {
+			merged = append(merged, entry)
+		}
+	}
+	return merged, nil
+}
+
+// environOptions are the options only used by Environ.
+type environOptions struct {
+	// base is nil if no environment should be merged.
+	base       []string
+	precedence EnvironPrecedence
+	// prefixes is nil if keys shouldn't be filtered.
+	prefixes []string
+	// fields is nil if fields shouldn't be filtered.
+	fields []string
+}
+
+func (o *environOptions) allowsKey(key string) bool {
+	if o.prefixes == nil {
+		return true
+	}
+	for _, prefix := range o.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsField checks whether the field itself or any of the structs
+// containing it are part of the allowlist.
+func (o *environOptions) allowsField(path string) bool {
+	if o.fields == nil {
+		return true
+	}
+	for _, field := range o.fields {
+		if path == field || strings.HasPrefix(path, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// environKey returns the key of a "KEY=VALUE" entry. On Windows, keys may
+// start with '=', which is why it is skipped when searching the separator.
+func environKey(entry string) string {
+	if entry == "" {
+		return ""
+	}
+	if index := strings.IndexByte(entry[1:], '='); index != -1 {
+		return entry[:index+1]
+	}
+	return entry
+}
@@ -0,0 +1,47 @@
+package env
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// WriteExample writes a documented .env example file for the given
+// configuration struct. Each variable is preceded by comments containing its
+// description, taken from the `desc` or `doc` tag, its type and whether it is
+// required. Defaults are taken from non-zero values of the given struct or
+// from the `default` tag. Defaults of secret fields are never written.
+//
+// The keys are derived the same way as in the source created by Source,
+// which can be configured using the given options.
+func WriteExample(writer io.Writer, configurationStruct any, opts ...Option) error {
+	variables, err := Describe(configurationStruct, opts...)
+	if err != nil {
+		return err
+	}
+
+	bufferedWriter := bufio.NewWriter(writer)
+	for index, variable := range variables {
+		if index > 0 {
+			bufferedWriter.WriteByte('\n')
+		}
+		if variable.Description != "" {
+			for _, line := range strings.Split(variable.Description, "\n") {
+				bufferedWriter.WriteString("# " + line + "\n")
+			}
+		}
+
+		bufferedWriter.WriteString("# Type: " + variable.Type)
+		if variable.Required {
+			bufferedWriter.WriteString(" (required)")
+		}
+		bufferedWriter.WriteByte('\n')
+
+		bufferedWriter.WriteString(variable.Key + "=")
+		if variable.Default != nil && !variable.Secret {
+			bufferedWriter.WriteString(quoteValue(*variable.Default))
+		}
+		bufferedWriter.WriteByte('\n')
+	}
+	return bufferedWriter.Flush()
+}
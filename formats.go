@@ -0,0 +1,212 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedFormat is thrown if an unknown Format has been configured.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Format defines the file format of the data source. No matter the format,
+// the keys found are mapped to environment variable keys and then parsed
+// exactly the same way.
+type Format string
+
+const (
+	// FormatEnv is the default format, whose grammar can be configured via
+	// Dialect.
+	FormatEnv Format = "env"
+	// FormatProperties reads Java style .properties files, including line
+	// continuations and escape sequences such as \uXXXX. Keys such as
+	// "database.host" are mapped to DATABASE_HOST by default.
+	FormatProperties Format = "properties"
+	// FormatINI reads INI files. Comments start with ';' or '#', values may
+	// be surrounded by double quotes and keys are prefixed with their
+	// section, so that "host" in section "[database]" is mapped to
+	// DATABASE_HOST by default. Inline comments aren't supported.
+	FormatINI Format = "ini"
+)
+
+// parseContent parses the content of the data source according to the
+// configured format and maps the keys.
+func (s *envSourceImpl) parseContent(content []byte) ([]variable, error) {
+	var (
+		variables []variable
+		err       error
+	)
+	keyMapping := s.keyMapping
+	switch s.format {
+	case "", FormatEnv:
+		variables, err = parseDocument(s.dialect, s.path, content)
+	case FormatProperties:
+		variables, err = newDotenvParser(s.path, content).parseProperties()
+	case FormatINI:
+		variables, err = newDotenvParser(s.path, content).parseINI()
+	default:
+		return nil, fmt.Errorf("format '%s': %w", s.format, ErrUnsupportedFormat)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if keyMapping == nil && s.format != "" && s.format != FormatEnv {
+		keyMapping = defaultKeyMapping
+	}
+	if keyMapping != nil {
+		for index := range variables {
+			variables[index].key = keyMapping(variables[index].key)
+		}
+	}
+	return variables, nil
+}
+
+// defaultKeyMapping maps hierarchical keys, such as "database.host", to
+// environment variable keys, such as DATABASE_HOST.
+func defaultKeyMapping(key string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// parseProperties mirrors java.util.Properties.load, except that the content
+// is expected to be UTF-8 encoded.
+func (p *dotenvParser) parseProperties() ([]variable, error) {
+	for p.offset < len(p.content) {
+		// Whitespace is skipped on all natural lines, including
+		// continuations.
+		p.skipPropertiesWhitespace()
+		if p.atLineEnd() {
+			p.skipLineEnd()
+			continue
+		}
+		if p.peek() == '#' || p.peek() == '!' {
+			for !p.atLineEnd() {
+				p.offset++
+			}
+			continue
+		}
+
+		definition := variable{line: p.line}
+		key, err := p.readPropertiesElement(true)
+		if err != nil {
+			return nil, err
+		}
+		p.skipPropertiesWhitespace()
+		if p.peek() == '=' || p.peek() == ':' {
+			p.offset++
+			p.skipPropertiesWhitespace()
+		}
+		value, err := p.readPropertiesElement(false)
+		if err != nil {
+			return nil, err
+		}
+
+		definition.key, definition.value = key, value
+		p.define(definition)
+	}
+	return p.variables, nil
+}
+
+func (p *dotenvParser) skipPropertiesWhitespace() {
+	for p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\f' {
+		p.offset++
+	}
+}
+
+// readPropertiesElement reads a key or a value, resolving escape sequences
+// and line continuations. Keys end at the first unescaped whitespace, '=' or
+// ':', while values end at the end of the logical line.
+func (p *dotenvParser) readPropertiesElement(isKey bool) (string, error) {
+	var builder strings.Builder
+	for !p.atLineEnd() {
+		character := p.content[p.offset]
+		if isKey && strings.IndexByte(" \t\f=:", character) != -1 {
+			break
+		}
+		if character != '\\' {
+			builder.WriteByte(character)
+			p.offset++
+			continue
+		}
+
+		escapeColumn := p.column()
+		p.offset++
+		if p.offset == len(p.content) {
+			break
+		}
+		switch escaped := p.content[p.offset]; escaped {
+		case '\r', '\n':
+			p.skipLineEnd()
+			p.skipPropertiesWhitespace()
+			continue
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			if p.offset+5 > len(p.content) {
+				return "", p.errorAt(p.line, escapeColumn, "malformed \\uXXXX encoding")
+			}
+			codePoint, err := strconv.ParseUint(p.content[p.offset+1:p.offset+5], 16, 16)
+			if err != nil {
+				return "", p.errorAt(p.line, escapeColumn, "malformed \\uXXXX encoding")
+			}
+			builder.WriteRune(rune(codePoint))
+			p.offset += 4
+		default:
+			builder.WriteByte(escaped)
+		}
+		p.offset++
+	}
+	return builder.String(), nil
+}
+
+// parseINI parses INI files, prefixing each key with the name of its section
+// and a '.'.
+func (p *dotenvParser) parseINI() ([]variable, error) {
+	var section string
+	for p.offset < len(p.content) {
+		lineStart := p.offset
+		for !p.atLineEnd() {
+			p.offset++
+		}
+		rawLine := p.content[lineStart:p.offset]
+		line := strings.TrimSpace(rawLine)
+		column := utf8.RuneCountInString(rawLine[:len(rawLine)-len(strings.TrimLeft(rawLine, " \t"))]) + 1
+		lineNumber := p.line
+		p.skipLineEnd()
+
+		switch {
+		case line == "" || line[0] == ';' || line[0] == '#':
+		case line[0] == '[':
+			if line[len(line)-1] != ']' {
+				return nil, p.errorAt(lineNumber, column, "unterminated section header")
+			}
+			section = strings.TrimSpace(line[1:len(line)-1]) + "."
+			if section == "." {
+				return nil, p.errorAt(lineNumber, column, "missing section name")
+			}
+		default:
+			delimiter := strings.IndexAny(line, "=:")
+			if delimiter == -1 {
+				return nil, p.errorAt(lineNumber, column, fmt.Sprintf("missing '=' after key '%s'", line))
+			}
+			key := strings.TrimSpace(line[:delimiter])
+			if key == "" {
+				return nil, p.errorAt(lineNumber, column, "missing key")
+			}
+			value := strings.TrimSpace(line[delimiter+1:])
+			if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+				value = value[1 : len(value)-1]
+			}
+			p.define(variable{key: section + key, value: value, line: lineNumber})
+		}
+	}
+	return p.variables, nil
+}
@@ -0,0 +1,62 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// Paths implements EnvSourceSetupStepOne.Paths.
+func (s *envSourceImpl) Paths(patterns ...string) EnvSourceSetupStepTwoEnvFile[*envSourceImpl] {
+	s.patterns = patterns
+	return s
+}
+
+// loadFragments reads all files matching the configured patterns, returning
+// their definitions in the order they are read in. Each file is parsed on its
+// own, so keys defined in multiple files are overrides, not duplicates.
+func (s *envSourceImpl) loadFragments() ([]variable, error) {
+	var (
+		variables []variable
+		loaded    bool
+	)
+	for _, pattern := range s.patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern '%s': %w", pattern, err)
+		}
+		if len(paths) == 0 && s.must {
+			return nil, fmt.Errorf("fragment '%s': %w", pattern, yagcl.ErrSourceNotFound)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			fragment := *s
+			fragment.patterns = nil
+			fragment.path = path
+			fragmentVariables, err := fragment.load()
+			// The file might have been removed since globbing. Other errors,
+			// such as syntax errors, already name the file.
+			if errors.Is(err, fs.ErrNotExist) {
+				if !s.must {
+					continue
+				}
+				return nil, fmt.Errorf("fragment '%s': %w", path, yagcl.ErrSourceNotFound)
+			}
+			if err != nil {
+				return nil, err
+			}
+			variables = append(variables, fragmentVariables...)
+			loaded = true
+		}
+	}
+
+	if !loaded {
+		return nil, yagcl.ErrSourceNotFound
+	}
+	return variables, nil
+}
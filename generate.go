@@ -0,0 +1,407 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/Bios-Marcel/yagcl-env/internal/codec"
+)
+
+// ErrNotGeneratable is thrown by WriteParser if the configuration struct
+// uses features that generated parsers don't support.
+var ErrNotGeneratable = errors.New("configuration struct not supported by generated parsers")
+
+// GeneratorBuildTag excludes generated parsers from the build. The yagcl-env
+// command sets it when running the program that inspects the configuration
+// struct, so that outdated parsers, which might not compile anymore, don't
+// prevent regenerating them.
+const GeneratorBuildTag = "yagclenv_generate"
+
+var parserTemplate = template.Must(template.New("parser").Parse(`// Code generated by yagcl-env. DO NOT EDIT.
+
+//go:build !{{.BuildTag}}
+
+package {{.PackageName}}
+
+import "github.com/Bios-Marcel/yagcl-env/envgen"
+
+// {{.FunctionName}} parses {{.TypeName}} from the given lookup, such as os.LookupEnv.
+func {{.FunctionName}}(lookup func(string) (string, bool)) ({{.TypeName}}, error) {
+	var c {{.TypeName}}
+	err := {{.FunctionName}}Into(lookup, &c)
+	return c, err
+}
+
+// {{.FunctionName}}Into parses {{.TypeName}} from the given lookup, such as
+// os.LookupEnv, preserving all values that have been set beforehand.
+func {{.FunctionName}}Into(lookup func(string) (string, bool), c *{{.TypeName}}) error {
+{{.Body}}
+}
+`))
+
+// WriteParser writes the source code of a function called functionName,
+// that parses the given configuration struct without using reflection. The
+// function has the signature
+//
+//	func(lookup func(string) (string, bool)) (Config, error)
+//
+// and is accompanied by a function with the suffix "Into", which parses into
+// an existing struct. The code has to be placed in the package defining the
+// struct. Keys are derived at generation time, using the given options.
+//
+// The generated code follows the same rules as the source created by Source,
+// but doesn't support validation tags. If those are present, an error
+// wrapping ErrNotGeneratable is returned. The file is excluded from builds
+// using GeneratorBuildTag.
+func WriteParser(writer io.Writer, configurationStruct any, packageName, functionName string, opts ...Option) error {
+	o := newOptions(opts)
+
+	structValue := reflect.Indirect(reflect.ValueOf(configurationStruct))
+	if structValue.Kind() != reflect.Struct {
+		return yagcl.ErrInvalidConfiguraionPointer
+	}
+	structType := structValue.Type()
+	if structType.Name() == "" {
+		return fmt.Errorf("anonymous struct types can't be referenced: %w", ErrNotGeneratable)
+	}
+
+	plan, err := o.source.compilePlan(o.parsingCompanion, o.source.prefix, "", false, structType)
+	if err != nil {
+		return err
+	}
+
+	generator := &parserGenerator{packagePath: structType.PkgPath()}
+	if err := generator.fields(plan, "c"); err != nil {
+		return err
+	}
+	if generator.hasRequiredKeys {
+		generator.body.WriteString("return envgen.MissingKeys(missing)\n")
+	} else {
+		generator.body.WriteString("return nil\n")
+	}
+
+	body := generator.body.String()
+	if generator.hasRequiredKeys {
+		body = "var missing []string\n" + body
+	}
+
+	var source bytes.Buffer
+	if err := parserTemplate.Execute(&source, map[string]any{
+		"BuildTag":     GeneratorBuildTag,
+		"PackageName":  packageName,
+		"FunctionName": functionName,
+		"TypeName":     structType.Name(),
+		"Body":         strings.TrimSpace(body),
+	}); err != nil {
+		return err
+	}
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return fmt.Errorf("generated code is invalid: %w", err)
+	}
+	_, err = writer.Write(formatted)
+	return err
+}
+
+// parserGenerator produces the statements mirroring envSourceImpl.parse for
+// a compiled plan. Since the generated code lives in the package of the
+// configuration struct, types are never named, but inferred via the generic
+// helpers of the envgen package.
+type parserGenerator struct {
+	body bytes.Buffer
+	// packagePath is the package containing the generated code, which is
+	// the only package whose unexported fields can be accessed.
+	packagePath     string
+	hasRequiredKeys bool
+	// structCount is used for naming the variables holding nested structs.
+	structCount int
+}
+
+func (g *parserGenerator) fields(plan *structPlan, base string) error {
+	for _, field := range plan.fields {
+		if err := g.field(field, base+"."+field.structField.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *parserGenerator) field(field *fieldPlan, expr string) error {
+	if field.rules != nil {
+		return fmt.Errorf("field '%s' uses validation tags: %w", field.structField.Name, ErrNotGeneratable)
+	}
+
+	depth := pointerDepth(field.structField.Type)
+	if field.nested != nil {
+		return g.nestedStruct(field, expr, depth)
+	}
+
+	var assign string
+	var err error
+	if field.unmarshalsText {
+		assign = g.unmarshalText(field, expr, depth)
+	} else {
+		assign, err = g.parseValue(field, expr, depth)
+	}
+	if err != nil {
+		return err
+	}
+
+	key := strconv.Quote(field.joinedEnvKey)
+	if !field.hasDefault {
+		fmt.Fprintf(&g.body, "if value, ok := lookup(%s); ok {\n%s}", key, assign)
+		if field.options.required {
+			g.hasRequiredKeys = true
+			fmt.Fprintf(&g.body, " else {\nmissing = append(missing, %s)\n}", key)
+		}
+		g.body.WriteString("\n")
+		return nil
+	}
+
+	isZero, err := g.isZero(expr, field.structField.Type)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&g.body, "{\nset := func(value string) error {\n%sreturn nil\n}\n", assign)
+	fmt.Fprintf(&g.body, "if value, ok := lookup(%s); ok {\nif err := set(value); err != nil {\nreturn err\n}\n} else ", key)
+	if field.options.required {
+		g.hasRequiredKeys = true
+		fmt.Fprintf(&g.body, "{\nmissing = append(missing, %s)\n", key)
+	}
+	// Defaults must not overwrite values that have been set beforehand.
+	fmt.Fprintf(&g.body, "if %s {\nif err := set(%s); err != nil {\nreturn envgen.InvalidDefault(%s, err)\n}\n}\n",
+		isZero, strconv.Quote(field.defaultValue), strconv.Quote(field.structField.Name))
+	if field.options.required {
+		g.body.WriteString("}\n")
+	}
+	g.body.WriteString("}\n")
+	return nil
+}
+
+// nestedStruct parses into existing structs, while new structs are only
+// assigned if any of their fields have been set.
+func (g *parserGenerator) nestedStruct(field *fieldPlan, expr string, depth int) error {
+	if depth == 0 {
+		return g.fields(field.nested, expr)
+	}
+
+	g.structCount++
+	variable := fmt.Sprintf("s%d", g.structCount)
+	fmt.Fprintf(&g.body, "// %s\n{\n%s := envgen.New(%s)\n", field.structField.Name, variable, zeroElem(expr, depth-1))
+	fmt.Fprintf(&g.body, "existing := %s\nif existing {\n%s = %s\n}\n", existingPointer(expr, depth), variable, dereferenceExpr(expr, depth-1))
+	if err := g.fields(field.nested, variable); err != nil {
+		return err
+	}
+
+	isNonZero, err := g.isNonZero("*"+variable, field.underlyingType)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&g.body, "if !existing && %s {\n%s = %s\n}\n}\n", isNonZero, expr, wrapPointers("*"+variable, depth))
+	return nil
+}
+
+func (g *parserGenerator) unmarshalText(field *fieldPlan, expr string, depth int) string {
+	var code strings.Builder
+	if depth == 0 {
+		// Preserve potential defaults set in non-pointer value.
+		fmt.Fprintf(&code, "target := envgen.Pointer(%s)\n", expr)
+	} else {
+		fmt.Fprintf(&code, "target := envgen.New(%s)\n", zeroElem(expr, depth-1))
+		fmt.Fprintf(&code, "if %s {\ntarget = %s\n}\n", existingPointer(expr, depth), dereferenceExpr(expr, depth-1))
+	}
+	fmt.Fprintf(&code, "if err := envgen.UnmarshalText(%s, %s, value, target); err != nil {\nreturn err\n}\n",
+		strconv.Quote(field.structField.Name), strconv.Quote(field.underlyingType.String()))
+	fmt.Fprintf(&code, "%s = %s\n", expr, wrapPointers("*target", depth))
+	return code.String()
+}
+
+func (g *parserGenerator) parseValue(field *fieldPlan, expr string, depth int) (string, error) {
+	fieldName := field.structField.Name
+	fieldType := field.underlyingType
+
+	var code strings.Builder
+	fmt.Fprintf(&code, "v := %s\n", zeroElem(expr, depth))
+
+	switch {
+	case isBinary(fieldType, field.byteEncoding):
+		if _, err := codec.DecodeBytes(field.byteEncoding, ""); err == ErrUnsupportedByteEncoding {
+			return "", fmt.Errorf("field '%s' specifies unsupported encoding '%s': %w", fieldName, field.byteEncoding, ErrUnsupportedByteEncoding)
+		}
+		if fieldType.Kind() == reflect.Slice {
+			fmt.Fprintf(&code, "if err := envgen.ParseBytes(%s, %s, value, &v); err != nil {\nreturn err\n}\n", strconv.Quote(fieldName), strconv.Quote(field.byteEncoding))
+		} else {
+			fmt.Fprintf(&code, "if err := envgen.ParseByteArray(%s, %s, value, v[:]); err != nil {\nreturn err\n}\n", strconv.Quote(fieldName), strconv.Quote(field.byteEncoding))
+		}
+	case fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array:
+		elementType := fieldType.Elem()
+		elementDepth := pointerDepth(elementType)
+		call, err := scalarParseCall(fieldName, extractNonPointerFieldType(elementType), "part", "e")
+		if err != nil {
+			return "", err
+		}
+
+		code.WriteString("parts := envgen.Split(value, ',')\n")
+		if fieldType.Kind() == reflect.Slice {
+			code.WriteString("v = envgen.MakeSlice(v, len(parts))\n")
+		} else {
+			fmt.Fprintf(&code, "if err := envgen.CheckArrayLength(%s, len(v), len(parts)); err != nil {\nreturn err\n}\n", strconv.Quote(fieldName))
+		}
+		fmt.Fprintf(&code, "for i, part := range parts {\ne := %s\nif err := %s; err != nil {\nreturn err\n}\nv[i] = %s\n}\n",
+			zeroElem("v[i]", elementDepth), call, wrapPointers("e", elementDepth))
+	case fieldType.Kind() == reflect.Map:
+		if fieldType.Key().Kind() == reflect.Pointer || fieldType.Elem().Kind() == reflect.Pointer {
+			return "", fmt.Errorf("field '%s' has unsupported type '%s': %w", fieldName, fieldType.String(), yagcl.ErrUnsupportedFieldType)
+		}
+		keyCall, err := scalarParseCall(fieldName, fieldType.Key(), "rawKey", "key")
+		if err != nil {
+			return "", err
+		}
+		elementCall, err := scalarParseCall(fieldName, fieldType.Elem(), "rawElement", "element")
+		if err != nil {
+			return "", err
+		}
+
+		code.WriteString("entries := envgen.Split(value, ',')\nv = envgen.MakeMap(v, len(entries))\n")
+		fmt.Fprintf(&code, "for index, entry := range entries {\nrawKey, rawElement, err := envgen.SplitMapEntry(%s, index, entry)\nif err != nil {\nreturn err\n}\n", strconv.Quote(fieldName))
+		code.WriteString("key, element := envgen.MapZero(v)\n")
+		fmt.Fprintf(&code, "if err := %s; err != nil {\nreturn envgen.InvalidMapKey(%s, rawKey)\n}\n", keyCall, strconv.Quote(fieldName))
+		fmt.Fprintf(&code, "if err := %s; err != nil {\nreturn envgen.InvalidMapValue(%s, rawElement)\n}\n", elementCall, strconv.Quote(fieldName))
+		code.WriteString("v[key] = element\n}\n")
+	default:
+		call, err := scalarParseCall(fieldName, fieldType, "value", "v")
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&code, "if err := %s; err != nil {\nreturn err\n}\n", call)
+	}
+
+	// Parsed zero values are ignored, just like in envSourceImpl.parse.
+	isNonZero, err := g.isNonZero("v", fieldType)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&code, "if %s {\n%s = %s\n}\n", isNonZero, expr, wrapPointers("v", depth))
+	return code.String(), nil
+}
+
+// scalarParseCall returns a call parsing the given value into the given
+// addressable target, that returns an error.
+func scalarParseCall(fieldName string, fieldType reflect.Type, value, target string) (string, error) {
+	quotedFieldName := strconv.Quote(fieldName)
+	quotedTypeName := strconv.Quote(fieldType.String())
+	switch fieldType.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("envgen.ParseString(%s, &%s)", value, target), nil
+	case reflect.Int64:
+		if fieldType == reflect.TypeOf(time.Duration(0)) {
+			return fmt.Sprintf("envgen.ParseDuration(%s, %s, &%s)", quotedFieldName, value, target), nil
+		}
+		fallthrough
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return fmt.Sprintf("envgen.ParseInt(%s, %s, %s, &%s)", quotedFieldName, quotedTypeName, value, target), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("envgen.ParseUint(%s, %s, %s, &%s)", quotedFieldName, quotedTypeName, value, target), nil
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("envgen.ParseFloat(%s, %s, %s, &%s)", quotedFieldName, quotedTypeName, value, target), nil
+	case reflect.Bool:
+		return fmt.Sprintf("envgen.ParseBool(%s, %s, %s, &%s)", quotedFieldName, quotedTypeName, value, target), nil
+	}
+	return "", fmt.Errorf("field '%s' has unsupported type '%s': %w", fieldName, fieldType.String(), yagcl.ErrUnsupportedFieldType)
+}
+
+// isZero returns an expression equivalent to reflect.Value.IsZero.
+func (g *parserGenerator) isZero(expr string, exprType reflect.Type) (string, error) {
+	switch exprType.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return expr + " == nil", nil
+	}
+	if exprType.Comparable() {
+		return fmt.Sprintf("envgen.IsZero(%s)", expr), nil
+	}
+
+	if exprType.Kind() == reflect.Struct {
+		checks := make([]string, 0, exprType.NumField())
+		for i := 0; i < exprType.NumField(); i++ {
+			structField := exprType.Field(i)
+			if structField.Name == "_" || (!structField.IsExported() && structField.PkgPath != g.packagePath) {
+				return "", fmt.Errorf("type '%s' isn't comparable and field '%s' is inaccessible: %w", exprType.String(), structField.Name, ErrNotGeneratable)
+			}
+			check, err := g.isZero("("+expr+")."+structField.Name, structField.Type)
+			if err != nil {
+				return "", err
+			}
+			checks = append(checks, check)
+		}
+		return "(" + strings.Join(checks, " && ") + ")", nil
+	}
+
+	return "", fmt.Errorf("type '%s' isn't comparable: %w", exprType.String(), ErrNotGeneratable)
+}
+
+// isNonZero negates the expression returned by isZero.
+func (g *parserGenerator) isNonZero(expr string, exprType reflect.Type) (string, error) {
+	isZero, err := g.isZero(expr, exprType)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(isZero, " == nil") {
+		return strings.TrimSuffix(isZero, " == nil") + " != nil", nil
+	}
+	return "!" + isZero, nil
+}
+
+func pointerDepth(fieldType reflect.Type) int {
+	depth := 0
+	for ; fieldType.Kind() == reflect.Pointer; fieldType = fieldType.Elem() {
+		depth++
+	}
+	return depth
+}
+
+// zeroElem returns an expression producing the zero value of the type found
+// after dereferencing the given expression depth times.
+func zeroElem(expr string, depth int) string {
+	if depth == 0 {
+		return fmt.Sprintf("envgen.ZeroValue(%s)", expr)
+	}
+	for i := 0; i < depth; i++ {
+		expr = fmt.Sprintf("envgen.ZeroElem(%s)", expr)
+	}
+	return expr
+}
+
+// existingPointer returns a condition checking that none of the pointers
+// are nil.
+func existingPointer(expr string, depth int) string {
+	conditions := make([]string, 0, depth)
+	for i := 0; i < depth; i++ {
+		conditions = append(conditions, dereferenceExpr(expr, i)+" != nil")
+	}
+	return strings.Join(conditions, " && ")
+}
+
+func dereferenceExpr(expr string, depth int) string {
+	if depth == 0 {
+		return expr
+	}
+	return strings.Repeat("*", depth) + expr
+}
+
+// wrapPointers is the equivalent of convertValueToPointerIfRequired.
+func wrapPointers(expr string, depth int) string {
+	for i := 0; i < depth; i++ {
+		expr = fmt.Sprintf("envgen.Pointer(%s)", expr)
+	}
+	return expr
+}
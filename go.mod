@@ -2,7 +2,4 @@ module github.com/Bios-Marcel/yagcl-env
 
 go 1.18
 
-require (
-	github.com/Bios-Marcel/yagcl v0.0.3
-	github.com/subosito/gotenv v1.4.3-0.20230429135202-648c35bf333e
-)
+require github.com/Bios-Marcel/yagcl v0.0.3
@@ -0,0 +1,167 @@
+// Package codec contains the value syntax shared by the reflection based
+// parser and the envgen runtime of generated parsers, so that both behave the
+// same. It doesn't use reflection, as generated parsers must work without it.
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// ErrUnsupportedByteEncoding is thrown if the `encoding` tag of a []byte or
+// [N]byte field contains an unknown value.
+var ErrUnsupportedByteEncoding = errors.New("unsupported byte encoding; use raw, base64, base64url or hex")
+
+// ErrRequiredKeysMissing is thrown if fields marked via `env:"KEY,required"`
+// couldn't be found in the source. The error message lists all missing keys.
+var ErrRequiredKeysMissing = errors.New("required keys missing in source")
+
+// Byte encodings supported by DecodeBytes.
+const (
+	ByteEncodingRaw       = "raw"
+	ByteEncodingBase64    = "base64"
+	ByteEncodingBase64URL = "base64url"
+	ByteEncodingHex       = "hex"
+)
+
+// Split splits the given "literal" at each "splitChar" found.
+// Additionally it allows you to escape the "splitChar" by using "\", which
+// on the other hand can be escaped the same way. An unescaped "splitChar" at
+// the end terminates the last value instead of starting a new one, so "a,"
+// results in ["a"], while "a,," results in ["a", ""]. An empty literal
+// results in no values.
+func Split(literal string, splitChar rune) []string {
+	var values []string
+	var buffer []rune
+	var escapeNext, terminated bool
+	for _, character := range literal {
+		escape := escapeNext
+		escapeNext = false
+		terminated = false
+
+		if character == splitChar && !escape {
+			values = append(values, string(buffer))
+			buffer = buffer[:0]
+			terminated = true
+		} else if character == '\\' && !escape {
+			escapeNext = true
+		} else {
+			buffer = append(buffer, character)
+		}
+	}
+	// A backslash at the end doesn't escape anything and is kept as is.
+	if escapeNext {
+		buffer = append(buffer, '\\')
+	}
+
+	if literal == "" || terminated {
+		return values
+	}
+	return append(values, string(buffer))
+}
+
+// DecodeBytes decodes the given value using the given encoding. An empty
+// encoding equals ByteEncodingRaw.
+func DecodeBytes(byteEncoding string, value string) ([]byte, error) {
+	switch byteEncoding {
+	case "", ByteEncodingRaw:
+		return []byte(value), nil
+	case ByteEncodingBase64:
+		return base64.StdEncoding.DecodeString(value)
+	case ByteEncodingBase64URL:
+		// Since padding is often stripped for URL-safe values, we allow both.
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	case ByteEncodingHex:
+		return hex.DecodeString(value)
+	default:
+		return nil, ErrUnsupportedByteEncoding
+	}
+}
+
+// DecodeField decodes the binary data of a field, wrapping errors.
+func DecodeField(fieldName, byteEncoding, value string) ([]byte, error) {
+	decoded, err := DecodeBytes(byteEncoding, value)
+	if err != nil {
+		if err == ErrUnsupportedByteEncoding {
+			return nil, fmt.Errorf("field '%s' specifies unsupported encoding '%s': %w", fieldName, byteEncoding, ErrUnsupportedByteEncoding)
+		}
+		return nil, fmt.Errorf("value for field '%s' isn't valid '%s' data; %s: %w", fieldName, byteEncoding, err, yagcl.ErrParseValue)
+	}
+	return decoded, nil
+}
+
+// DecodeArray decodes the binary data of a field into the given slice of a
+// byte array, requiring the decoded data to have the exact same length.
+func DecodeArray(fieldName, byteEncoding, value string, out []byte) error {
+	decoded, err := DecodeField(fieldName, byteEncoding, value)
+	if err != nil {
+		return err
+	}
+
+	// Fixed size arrays are usually used for things such as keys, where the
+	// length is important, therefore we don't allow any mismatch.
+	if len(out) != len(decoded) {
+		return fmt.Errorf("value specified for field '%s' decodes to %d bytes, but expected exactly %d bytes: %w", fieldName, len(decoded), len(out), yagcl.ErrParseValue)
+	}
+	copy(out, decoded)
+	return nil
+}
+
+// ParseFloat parses a float, accepting the same syntax as encoding/json,
+// including surrounding whitespace and `null`, which results in zero.
+func ParseFloat(fieldName, typeName, value string) (float64, error) {
+	trimmed := strings.Trim(value, " \t\r\n")
+	if trimmed == "null" {
+		return 0, nil
+	}
+
+	if isJSONNumber(trimmed) {
+		if parsed, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return parsed, nil
+		}
+	}
+	return 0, fmt.Errorf("value '%s' isn't parsable as an '%s' for field '%s': %w", value, typeName, fieldName, yagcl.ErrParseValue)
+}
+
+// isJSONNumber checks the number grammar defined by RFC 8259.
+func isJSONNumber(value string) bool {
+	index := 0
+	digits := func() int {
+		start := index
+		for index < len(value) && value[index] >= '0' && value[index] <= '9' {
+			index++
+		}
+		return index - start
+	}
+
+	if index < len(value) && value[index] == '-' {
+		index++
+	}
+	if index < len(value) && value[index] == '0' {
+		index++
+	} else if digits() == 0 {
+		return false
+	}
+	if index < len(value) && value[index] == '.' {
+		index++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if index < len(value) && (value[index] == 'e' || value[index] == 'E') {
+		index++
+		if index < len(value) && (value[index] == '+' || value[index] == '-') {
+			index++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return index == len(value)
+}
@@ -0,0 +1,158 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrLoadConflict is thrown if LoadFailOnConflict is used and at least one
+// key is already present in the environment with a different value. The
+// error message lists all conflicting keys.
+var ErrLoadConflict = errors.New("keys already present in environment with different values")
+
+// LoadPolicy defines how LoadIntoEnv treats keys, that are already present in
+// the environment.
+type LoadPolicy int
+
+const (
+	// LoadOverwrite overwrites existing environment variables.
+	LoadOverwrite LoadPolicy = iota
+	// LoadKeepExisting keeps existing environment variables, which is the
+	// behaviour most dotenv implementations have.
+	LoadKeepExisting
+	// LoadFailOnConflict fails if any key is already present with a
+	// different value. In this case, no variable is set at all.
+	LoadFailOnConflict
+)
+
+// LoadResult describes which keys have been loaded into the environment.
+// Each list is sorted.
+type LoadResult struct {
+	// Set holds the keys that have been written into the environment.
+	Set []string
+	// Skipped holds the keys that haven't been written, since they were
+	// already present and LoadKeepExisting is used.
+	Skipped []string
+	// Conflicted holds the keys that were already present with a different
+	// value. Depending on the policy, these are also part of Set or Skipped.
+	Conflicted []string
+
+	// previous holds the values of the keys in Set before loading. The value
+	// is nil if a key wasn't present.
+	previous map[string]*string
+}
+
+// Restore resets the variables that have been set when loading to the values
+// they had before, unsetting those that weren't present. Calling Restore more
+// than once has no effect. If a variable can't be restored, the remaining
+// ones are still restored and the first error is returned.
+func (r *LoadResult) Restore() error {
+	var firstErr error
+	for key, previous := range r.previous {
+		var err error
+		if previous == nil {
+			err = os.Unsetenv(key)
+		} else {
+			err = os.Setenv(key, *previous)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	r.previous = nil
+	return firstErr
+}
+
+// LoadIntoEnv loads the variables of the data source defined via WithPath,
+// WithPaths, WithBytes, WithReader or WithDiscover into the environment of the
+// process, without parsing a configuration struct. The returned function
+// restores the previous environment, which is useful for tests and
+// subcommands. It is never nil, even if an error is returned.
+//
+//	restore, err := env.LoadIntoEnv(env.WithPath("testdata/.env"))
+//	if err != nil {
+//		return err
+//	}
+//	defer restore()
+func LoadIntoEnv(opts ...Option) (restore func() error, err error) {
+	o := newOptions(opts)
+	result := &LoadResult{}
+	o.source.LoadResult(result)
+	restore = result.Restore
+
+	defer func() {
+		err = o.source.sourceError(err)
+	}()
+
+	if err = o.source.verify(); err != nil {
+		return
+	}
+	source, err := o.source.resolve()
+	if err != nil {
+		return
+	}
+	variables, err := source.load()
+	if err != nil {
+		return
+	}
+	values, _ := indexVariables(variables)
+	err = source.loadIntoEnvironment(values)
+	return
+}
+
+// loadIntoEnvironment writes the given variables into the environment of the
+// process, according to the configured policy.
+func (s *envSourceImpl) loadIntoEnvironment(variables map[string]string) error {
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		if s.loadPrefixedOnly && !strings.HasPrefix(key, s.keyJoiner(s.prefix, "")) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := LoadResult{previous: make(map[string]*string, len(keys))}
+	for _, key := range keys {
+		existing, present := os.LookupEnv(key)
+		conflicted := present && existing != variables[key]
+		if conflicted {
+			result.Conflicted = append(result.Conflicted, key)
+		}
+		if present && s.loadPolicy == LoadKeepExisting {
+			result.Skipped = append(result.Skipped, key)
+		} else {
+			result.Set = append(result.Set, key)
+		}
+	}
+
+	defer func() {
+		if s.loadResult != nil {
+			*s.loadResult = result
+		}
+	}()
+
+	if s.loadPolicy == LoadFailOnConflict && len(result.Conflicted) > 0 {
+		result.Set = nil
+		return fmt.Errorf("keys [%s]: %w", strings.Join(result.Conflicted, ", "), ErrLoadConflict)
+	}
+
+	for _, key := range result.Set {
+		if existing, present := os.LookupEnv(key); present {
+			result.previous[key] = &existing
+		} else {
+			result.previous[key] = nil
+		}
+
+		if err := os.Setenv(key, variables[key]); err != nil {
+			// Either all variables are loaded or none.
+			result.Restore()
+			result.Set = nil
+			return err
+		}
+	}
+	return nil
+}
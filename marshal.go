@@ -0,0 +1,271 @@
+package env
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// RedactedValue replaces the values of secret fields if redaction has been
+// enabled via WithRedactedSecrets.
+const RedactedValue = "REDACTED"
+
+// secretTagName is the tag used to mark fields as secret, for example
+// `secret:"true"`. Secret values can be redacted when exporting them.
+const secretTagName = "secret"
+
+// Marshal writes the given configuration struct as a .env document. The keys
+// are built using the same rules as the source created by Source, which can
+// be configured using the given options. The keys are written in the order
+// of the fields of the struct, while map entries are sorted, making the
+// output deterministic. Nil pointers are omitted.
+func Marshal(configurationStruct any, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+
+	structValue := reflect.Indirect(reflect.ValueOf(configurationStruct))
+	if structValue.Kind() != reflect.Struct {
+		return nil, yagcl.ErrInvalidConfiguraionPointer
+	}
+
+	var buffer bytes.Buffer
+	err := o.walk(structValue, func(field walkedField) error {
+		if field.absent {
+			return nil
+		}
+
+		value, err := o.formatField(field)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return nil
+		}
+
+		buffer.WriteString(field.joinedEnvKey)
+		buffer.WriteByte('=')
+		buffer.WriteString(quoteValue(*value))
+		buffer.WriteByte('\n')
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// walkedField is a field with a value of its own, meaning it isn't a nested
+// struct.
+type walkedField struct {
+	*fieldPlan
+	// value can be a nil pointer.
+	value reflect.Value
+	// absent is true if any of the structs containing the field is a nil
+	// pointer. In this case, value is a zero value.
+	absent bool
+}
+
+// walk calls the given function for each field that has a value of its own,
+// using the same plan as envSourceImpl.parse. Nil pointers to structs are
+// walked using zero values, marking all fields as absent.
+func (o *options) walk(structValue reflect.Value, fn func(walkedField) error) error {
+	plan, err := o.source.plan(o.parsingCompanion, structValue.Type())
+	if err != nil {
+		return err
+	}
+	return walkPlan(plan, structValue, false, fn)
+}
+
+func walkPlan(plan *structPlan, structValue reflect.Value, absent bool, fn func(walkedField) error) error {
+	for _, field := range plan.fields {
+		value := structValue.Field(field.index)
+		if field.nested != nil {
+			nestedStruct, ok := dereference(value)
+			if !ok {
+				nestedStruct = reflect.Indirect(reflect.New(field.underlyingType))
+			}
+			if err := walkPlan(field.nested, nestedStruct, absent || !ok, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := fn(walkedField{fieldPlan: field, value: value, absent: absent}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isSecret(structField reflect.StructField) bool {
+	return strings.EqualFold(structField.Tag.Get(secretTagName), "true")
+}
+
+// formatField formats the value of the given field, taking redaction into
+// account. If the value is a nil pointer, nil is returned.
+func (o *options) formatField(field walkedField) (*string, error) {
+	if _, ok := dereference(field.value); !ok {
+		return nil, nil
+	}
+	if field.secret && o.redactSecrets {
+		redacted := RedactedValue
+		return &redacted, nil
+	}
+
+	formatted, err := formatValue(field.structField.Name, field.value, field.byteEncoding)
+	if err != nil {
+		return nil, err
+	}
+	return &formatted, nil
+}
+
+// formatValue is the counterpart to parseValue, producing a string, that
+// parseValue will turn back into the given value.
+func formatValue(fieldName string, value reflect.Value, byteEncoding string) (string, error) {
+	value, ok := dereference(value)
+	if !ok {
+		return "", nil
+	}
+
+	// Just like for parsing, custom marshallers take precedence.
+	if marshaler, ok := asTextMarshaler(value); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("value of field '%s' couldn't be marshalled; %s: %w", fieldName, err, yagcl.ErrParseValue)
+		}
+		return string(text), nil
+	}
+
+	if isBinary(value.Type(), byteEncoding) {
+		if value.Kind() == reflect.Array {
+			// Arrays aren't necessarily addressable, so we copy the data.
+			data := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(data), value)
+			return encodeBytes(fieldName, byteEncoding, data)
+		}
+		return encodeBytes(fieldName, byteEncoding, value.Bytes())
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Int64:
+		if value.Type().AssignableTo(reflect.TypeOf(time.Duration(0))) {
+			return time.Duration(value.Int()).String(), nil
+		}
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Slice, reflect.Array:
+		elements := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			element, err := formatValue(fieldName, value.Index(i), byteEncoding)
+			if err != nil {
+				return "", err
+			}
+			elements = append(elements, element)
+		}
+		return joinString(elements, ','), nil
+	case reflect.Map:
+		entries := make([]string, 0, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			key, err := formatValue(fieldName, iter.Key(), byteEncoding)
+			if err != nil {
+				return "", err
+			}
+			element, err := formatValue(fieldName, iter.Value(), byteEncoding)
+			if err != nil {
+				return "", err
+			}
+			// Since maps are split at commas first and at equal signs second,
+			// we have to join in the opposite order.
+			entries = append(entries, joinString([]string{key, element}, '='))
+		}
+		sort.Strings(entries)
+		return joinString(entries, ','), nil
+	}
+
+	return "", fmt.Errorf("field '%s' has unsupported type '%s': %w", fieldName, value.Type().String(), yagcl.ErrUnsupportedFieldType)
+}
+
+// asTextMarshaler checks whether either the value or a pointer to it
+// implements encoding.TextMarshaler.
+func asTextMarshaler(value reflect.Value) (encoding.TextMarshaler, bool) {
+	if !value.CanInterface() {
+		return nil, false
+	}
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		return marshaler, true
+	}
+
+	// Non-addressable values, such as map entries, have to be copied, in
+	// order to be able to call methods with pointer receivers.
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	marshaler, ok := pointer.Interface().(encoding.TextMarshaler)
+	return marshaler, ok
+}
+
+// encodeBytes is the counterpart to codec.DecodeBytes.
+func encodeBytes(fieldName, byteEncoding string, data []byte) (string, error) {
+	switch byteEncoding {
+	case "", ByteEncodingRaw:
+		return string(data), nil
+	case ByteEncodingBase64:
+		return base64.StdEncoding.EncodeToString(data), nil
+	case ByteEncodingBase64URL:
+		return base64.RawURLEncoding.EncodeToString(data), nil
+	case ByteEncodingHex:
+		return hex.EncodeToString(data), nil
+	}
+	return "", fmt.Errorf("field '%s' specifies unsupported encoding '%s': %w", fieldName, byteEncoding, ErrUnsupportedByteEncoding)
+}
+
+// quoteValue quotes the value if required. Unquoted values are only used if
+// they consist of characters that are guaranteed to be read as is.
+func quoteValue(value string) string {
+	if strings.IndexFunc(value, func(character rune) bool { return !isUnquotedValueRune(character) }) == -1 {
+		return value
+	}
+
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, character := range value {
+		switch character {
+		case '\\', '"', '$':
+			builder.WriteByte('\\')
+			builder.WriteRune(character)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		default:
+			builder.WriteRune(character)
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+func isUnquotedValueRune(character rune) bool {
+	return (character >= 'a' && character <= 'z') ||
+		(character >= 'A' && character <= 'Z') ||
+		(character >= '0' && character <= '9') ||
+		strings.ContainsRune("_-.,:/@+=%", character)
+}
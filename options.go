@@ -0,0 +1,251 @@
+package env
+
+import (
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// Option configures the functions of this package that can be used without
+// a yagcl pipeline, such as Parse or Marshal.
+type Option func(*options)
+
+type options struct {
+	// source holds the source and key related configuration, so that we can
+	// reuse the same logic as in envSourceImpl.Parse.
+	source           *envSourceImpl
+	parsingCompanion yagcl.ParsingCompanion
+	redactSecrets    bool
+	environ          environOptions
+
+	// These are only used for the default yagcl.ParsingCompanion.
+	additionalKeyTags []string
+	inferFieldKeys    bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		source: Source().(*envSourceImpl),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.parsingCompanion == nil {
+		o.parsingCompanion = &defaultParsingCompanion{
+			keyTags:        append([]string{yagcl.DefaultKeyTagName}, o.additionalKeyTags...),
+			inferFieldKeys: o.inferFieldKeys,
+		}
+	}
+	return o
+}
+
+// WithPath is the equivalent of EnvSourceSetupStepOne.Path. If no data
+// source is specified, the environment variables of the process are used.
+func WithPath(path string) Option {
+	return func(o *options) {
+		o.source.Path(path)
+	}
+}
+
+// WithBytes is the equivalent of EnvSourceSetupStepOne.Bytes. If no data
+// source is specified, the environment variables of the process are used.
+func WithBytes(bytes []byte) Option {
+	return func(o *options) {
+		o.source.Bytes(bytes)
+	}
+}
+
+// WithReader is the equivalent of EnvSourceSetupStepOne.Reader. If no data
+// source is specified, the environment variables of the process are used.
+func WithReader(reader io.Reader) Option {
+	return func(o *options) {
+		o.source.Reader(reader)
+	}
+}
+
+// WithPaths is the equivalent of EnvSourceSetupStepOne.Paths.
+func WithPaths(patterns ...string) Option {
+	return func(o *options) {
+		o.source.Paths(patterns...)
+	}
+}
+
+// WithDiscover is the equivalent of EnvSourceSetupStepOne.DiscoverFrom. If
+// directory is empty, the search starts at the current working directory.
+func WithDiscover(directory, name string) Option {
+	return func(o *options) {
+		o.source.DiscoverFrom(directory, name)
+	}
+}
+
+// WithMust is the equivalent of EnvSourceSetupStepTwoEnvFile.Must.
+func WithMust() Option {
+	return func(o *options) {
+		o.source.Must()
+	}
+}
+
+// WithLoadPolicy is the equivalent of
+// EnvSourceSetupStepTwoEnvFile.LoadIntoEnvPolicy. It only affects LoadIntoEnv.
+func WithLoadPolicy(policy LoadPolicy) Option {
+	return func(o *options) {
+		o.source.loadPolicy = policy
+	}
+}
+
+// WithLoadPrefixedOnly is the equivalent of
+// EnvSourceSetupStepTwoEnvFile.LoadPrefixedOnly. It only affects LoadIntoEnv.
+func WithLoadPrefixedOnly() Option {
+	return func(o *options) {
+		o.source.LoadPrefixedOnly()
+	}
+}
+
+// WithFormat is the equivalent of EnvSourceSetupStepTwoEnvFile.Format.
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.source.Format(format)
+	}
+}
+
+// WithKeyMapping is the equivalent of EnvSourceSetupStepTwoEnvFile.KeyMapping.
+func WithKeyMapping(keyMapping func(string) string) Option {
+	return func(o *options) {
+		o.source.KeyMapping(keyMapping)
+	}
+}
+
+// WithDialect is the equivalent of EnvSourceSetupStepTwoEnvFile.Dialect.
+func WithDialect(dialect Dialect) Option {
+	return func(o *options) {
+		o.source.Dialect(dialect)
+	}
+}
+
+// WithStrictDuplicates is the equivalent of
+// EnvSourceSetupStepTwoEnvFile.StrictDuplicates.
+func WithStrictDuplicates() Option {
+	return func(o *options) {
+		o.source.StrictDuplicates()
+	}
+}
+
+// WithOnDuplicate is the equivalent of EnvSourceSetupStepTwoEnvFile.OnDuplicate.
+func WithOnDuplicate(onDuplicate func(Duplicate)) Option {
+	return func(o *options) {
+		o.source.OnDuplicate(onDuplicate)
+	}
+}
+
+// WithPrefix is the equivalent of EnvSourceOptionalSetup.Prefix.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.source.Prefix(prefix)
+	}
+}
+
+// WithKeyValueConverter is the equivalent of
+// EnvSourceOptionalSetup.KeyValueConverter.
+func WithKeyValueConverter(keyValueConverter func(string) string) Option {
+	return func(o *options) {
+		o.source.KeyValueConverter(keyValueConverter)
+	}
+}
+
+// WithKeyJoiner is the equivalent of EnvSourceOptionalSetup.KeyJoiner.
+func WithKeyJoiner(keyJoiner func(string, string) string) Option {
+	return func(o *options) {
+		o.source.KeyJoiner(keyJoiner)
+	}
+}
+
+// WithParsingCompanion replaces the built-in yagcl.ParsingCompanion, which
+// mirrors the defaults of yagcl. This is required if your yagcl pipeline
+// uses non-default settings.
+func WithParsingCompanion(parsingCompanion yagcl.ParsingCompanion) Option {
+	return func(o *options) {
+		o.parsingCompanion = parsingCompanion
+	}
+}
+
+// WithAdditionalKeyTags is the equivalent of yagcl.YAGCL.AdditionalKeyTags.
+// This has no effect if WithParsingCompanion is used.
+func WithAdditionalKeyTags(tags ...string) Option {
+	return func(o *options) {
+		o.additionalKeyTags = append(o.additionalKeyTags, tags...)
+	}
+}
+
+// WithInferFieldKeys is the equivalent of yagcl.YAGCL.InferFieldKeys. This
+// has no effect if WithParsingCompanion is used.
+func WithInferFieldKeys() Option {
+	return func(o *options) {
+		o.inferFieldKeys = true
+	}
+}
+
+// WithRedactedSecrets causes the values of fields tagged with `secret:"true"`
+// to be replaced with RedactedValue.
+func WithRedactedSecrets() Option {
+	return func(o *options) {
+		o.redactSecrets = true
+	}
+}
+
+// WithMergedEnviron merges the result of Environ with the given "KEY=VALUE"
+// entries, usually os.Environ. The precedence defines which value is kept if
+// a key is part of both. It only affects Environ.
+func WithMergedEnviron(environ []string, precedence EnvironPrecedence) Option {
+	return func(o *options) {
+		o.environ.base = append([]string{}, environ...)
+		o.environ.precedence = precedence
+	}
+}
+
+// WithPrefixFilter restricts the result of Environ to keys starting with any
+// of the given prefixes, including the entries merged via WithMergedEnviron.
+// It only affects Environ.
+func WithPrefixFilter(prefixes ...string) Option {
+	return func(o *options) {
+		o.environ.prefixes = append(o.environ.prefixes, prefixes...)
+	}
+}
+
+// WithFieldAllowlist restricts the result of Environ to the given fields.
+// Fields are identified by their path, for example "Database.Host", while
+// nested structs, such as "Database", include all of their fields. It only
+// affects Environ.
+func WithFieldAllowlist(fields ...string) Option {
+	return func(o *options) {
+		o.environ.fields = append(o.environ.fields, fields...)
+	}
+}
+
+// defaultParsingCompanion mirrors the default behaviour of the
+// yagcl.ParsingCompanion provided by yagcl.New.
+type defaultParsingCompanion struct {
+	keyTags        []string
+	inferFieldKeys bool
+}
+
+// IncludeField implements yagcl.ParsingCompanion.IncludeField.
+func (c *defaultParsingCompanion) IncludeField(structField reflect.StructField) bool {
+	return structField.IsExported() && !strings.EqualFold(structField.Tag.Get("ignore"), "true")
+}
+
+// ExtractFieldKey implements yagcl.ParsingCompanion.ExtractFieldKey.
+func (c *defaultParsingCompanion) ExtractFieldKey(structField reflect.StructField) string {
+	for _, keyTag := range c.keyTags {
+		if key := structField.Tag.Get(keyTag); key != "" {
+			return key
+		}
+	}
+
+	if c.inferFieldKeys {
+		return strings.ToLower(structField.Name)
+	}
+
+	return ""
+}
@@ -0,0 +1,56 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// Parse parses a new instance of T without requiring a yagcl pipeline. By
+// default, the environment variables of the process are read, which can be
+// changed via WithPath, WithPaths, WithBytes, WithReader or WithDiscover.
+// Fields are included and their keys are extracted the same way yagcl does by
+// default.
+//
+//	cfg, err := env.Parse[Config](env.WithPrefix("APP"))
+func Parse[T any](opts ...Option) (T, error) {
+	var configuration T
+	if reflect.TypeOf(configuration) == nil || reflect.TypeOf(configuration).Kind() != reflect.Struct {
+		return configuration, yagcl.ErrInvalidConfiguraionPointer
+	}
+
+	o := newOptions(opts)
+	if o.source.path == "" && len(o.source.bytes) == 0 && o.source.reader == nil &&
+		len(o.source.patterns) == 0 && o.source.discoverName == "" {
+		o.source.Env()
+	}
+
+	_, err := o.source.Parse(o.parsingCompanion, &configuration)
+	return configuration, err
+}
+
+// ParseWithReport is like Parse, but additionally returns a Report, which
+// describes where the value of each field came from. The report is also
+// returned if parsing fails, as it might help finding the cause.
+//
+//	cfg, report, err := env.ParseWithReport[Config](env.WithPath(".env"))
+//	log.Println(report)
+func ParseWithReport[T any](opts ...Option) (T, *Report, error) {
+	report := &Report{}
+	// Prevent modifying the array backing the slice passed by the caller.
+	opts = append(opts[:len(opts):len(opts)], func(o *options) {
+		o.source.Report(report)
+	})
+	configuration, err := Parse[T](opts...)
+	return configuration, report, err
+}
+
+// MustParse is like Parse, but panics if an error occurs.
+func MustParse[T any](opts ...Option) T {
+	configuration, err := Parse[T](opts...)
+	if err != nil {
+		panic(fmt.Sprintf("parsing configuration failed: %s", err))
+	}
+	return configuration
+}
@@ -0,0 +1,223 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// structPlan is the precompiled decoding plan of a struct type. It holds all
+// information that only depends on the type and the source configuration,
+// so that repeated calls to Parse only have to do lookups and conversions.
+type structPlan struct {
+	fields []*fieldPlan
+}
+
+// fieldPlan is the precompiled decoding plan of a single included field.
+type fieldPlan struct {
+	index        int
+	structField  reflect.StructField
+	joinedEnvKey string
+	options      envTagOptions
+	// path is the path of the field starting at the configuration struct,
+	// for example "Database.Host".
+	path string
+	// secret is true if either the field or any of the structs containing
+	// it, are marked as secret.
+	secret bool
+
+	// underlyingType is the non-pointer type of the field.
+	underlyingType reflect.Type
+	// unmarshalsText is true if a pointer to underlyingType implements
+	// encoding.TextUnmarshaler.
+	unmarshalsText bool
+	byteEncoding   string
+
+	defaultValue string
+	hasDefault   bool
+
+	// rules is nil if no validation rules have been defined.
+	rules *validationRules
+
+	// nested is set for nested structs, which don't have a value of their
+	// own, but are parsed recursively.
+	nested *structPlan
+}
+
+// maxCachedPlans bounds the amount of plans held by a planCache, as both
+// the prefix and the yagcl.ParsingCompanion are part of the key and might be
+// created anew for each call to Parse.
+const maxCachedPlans = 64
+
+// sharedPlans holds the plans of all sources using the default key joiner and
+// key value converter, so that sources created per call, such as in Parse,
+// profit from it as well. Custom functions can't be compared, so sources
+// using them cache their plans themselves.
+var sharedPlans = newPlanCache()
+
+var (
+	defaultKeyJoinerPointer         = reflect.ValueOf(defaultKeyJoiner).Pointer()
+	defaultKeyValueConverterPointer = reflect.ValueOf(defaultKeyValueConverter).Pointer()
+)
+
+// planKey identifies a cached plan. Besides the struct type and the prefix,
+// a plan depends on the fields included by the yagcl.ParsingCompanion and on
+// the keys it extracts, see companionKey.
+type planKey struct {
+	structType reflect.Type
+	prefix     string
+	companion  any
+}
+
+// defaultCompanionKey identifies a defaultParsingCompanion by its settings,
+// as a new one is created for each call to Parse.
+type defaultCompanionKey struct {
+	keyTags        string
+	inferFieldKeys bool
+}
+
+// companionKey returns a comparable identity of the given companion. Other
+// companions than defaultParsingCompanion are identified by themselves, as
+// their behaviour can't be inspected. If they aren't comparable, false is
+// returned.
+func companionKey(parsingCompanion yagcl.ParsingCompanion) (any, bool) {
+	if companion, ok := parsingCompanion.(*defaultParsingCompanion); ok {
+		return defaultCompanionKey{
+			keyTags:        strings.Join(companion.keyTags, ","),
+			inferFieldKeys: companion.inferFieldKeys,
+		}, true
+	}
+	if !reflect.TypeOf(parsingCompanion).Comparable() {
+		return nil, false
+	}
+	return parsingCompanion, true
+}
+
+// planCache is a bounded, concurrency safe cache of compiled plans.
+type planCache struct {
+	mutex sync.RWMutex
+	plans map[planKey]*structPlan
+}
+
+func newPlanCache() *planCache {
+	return &planCache{plans: make(map[planKey]*structPlan)}
+}
+
+func (c *planCache) load(key planKey) (*structPlan, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	plan, ok := c.plans[key]
+	return plan, ok
+}
+
+// store adds the given plan, evicting an arbitrary plan if the cache is full.
+func (c *planCache) store(key planKey, plan *structPlan) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.plans) >= maxCachedPlans {
+		for existingKey := range c.plans {
+			delete(c.plans, existingKey)
+			break
+		}
+	}
+	c.plans[key] = plan
+}
+
+// reset drops all plans.
+func (c *planCache) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.plans = make(map[planKey]*structPlan)
+}
+
+// planCache returns the cache to use for the current key configuration.
+func (s *envSourceImpl) planCache() *planCache {
+	if reflect.ValueOf(s.keyJoiner).Pointer() == defaultKeyJoinerPointer &&
+		reflect.ValueOf(s.keyValueConverter).Pointer() == defaultKeyValueConverterPointer {
+		return sharedPlans
+	}
+	return s.plans
+}
+
+// plan returns the decoding plan for the given struct type, compiling it if
+// it hasn't been cached yet.
+func (s *envSourceImpl) plan(parsingCompanion yagcl.ParsingCompanion, structType reflect.Type) (*structPlan, error) {
+	companion, ok := companionKey(parsingCompanion)
+	if !ok {
+		return s.compilePlan(parsingCompanion, s.prefix, "", false, structType)
+	}
+
+	cache := s.planCache()
+	key := planKey{structType: structType, prefix: s.prefix, companion: companion}
+	if plan, ok := cache.load(key); ok {
+		return plan, nil
+	}
+
+	plan, err := s.compilePlan(parsingCompanion, s.prefix, "", false, structType)
+	if err != nil {
+		return nil, err
+	}
+	// Concurrent compilations produce equal plans, so it doesn't matter
+	// which one is kept.
+	cache.store(key, plan)
+	return plan, nil
+}
+
+func (s *envSourceImpl) compilePlan(
+	parsingCompanion yagcl.ParsingCompanion,
+	envPrefix string,
+	pathPrefix string,
+	secret bool,
+	structType reflect.Type,
+) (*structPlan, error) {
+	plan := &structPlan{}
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if !parsingCompanion.IncludeField(structField) {
+			continue
+		}
+
+		envKey, options, errExtractKey := s.extractEnvKey(parsingCompanion, structField)
+		if errExtractKey != nil {
+			return nil, errExtractKey
+		}
+
+		underlyingType := extractNonPointerFieldType(structField.Type)
+		field := &fieldPlan{
+			index:          i,
+			structField:    structField,
+			joinedEnvKey:   s.keyJoiner(envPrefix, envKey),
+			options:        options,
+			path:           pathPrefix + structField.Name,
+			secret:         secret || isSecret(structField),
+			underlyingType: underlyingType,
+			unmarshalsText: reflect.PointerTo(underlyingType).Implements(textUnmarshalerType),
+			byteEncoding:   byteEncodingOf(structField),
+		}
+		field.defaultValue, field.hasDefault = structField.Tag.Lookup(defaultTagName)
+
+		rules, errCompile := compileValidationRules(structField)
+		if errCompile != nil {
+			return nil, errCompile
+		}
+		field.rules = rules
+		if options.required && rules != nil && rules.required {
+			return nil, fmt.Errorf("field '%s': %w", structField.Name, ErrAmbiguousRequired)
+		}
+
+		if isNestedStruct(structField.Type) {
+			nested, errCompile := s.compilePlan(parsingCompanion, field.joinedEnvKey, field.path+".", field.secret, underlyingType)
+			if errCompile != nil {
+				return nil, errCompile
+			}
+			field.nested = nested
+		}
+
+		plan.fields = append(plan.fields, field)
+	}
+
+	return plan, nil
+}
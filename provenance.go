@@ -0,0 +1,216 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// SourceKind describes the kind of data source a value has been read from.
+type SourceKind string
+
+const (
+	// SourceKindEnvironment is used for sources reading the environment
+	// variables of the process.
+	SourceKindEnvironment SourceKind = "environment"
+	// SourceKindFile is used for sources reading the file at a path.
+	SourceKindFile SourceKind = "file"
+	// SourceKindBytes is used for sources reading from bytes or a string.
+	SourceKindBytes SourceKind = "bytes"
+	// SourceKindReader is used for sources reading from an io.Reader.
+	SourceKindReader SourceKind = "reader"
+)
+
+// Provenance describes where the value of a single field came from.
+type Provenance struct {
+	// Field is the path of the field, starting at the configuration struct,
+	// for example "Database.Host".
+	Field string
+	// Key is the fully joined key that has been looked up.
+	Key string
+	// Found is true if the key has been present in the source.
+	Found bool
+	// Default is true if the key couldn't be found and the value of the
+	// `default` tag has been used instead.
+	Default bool
+	// StructDefault is true if the key couldn't be found, but the field
+	// already held a non-zero value, for example because the struct has been
+	// pre-populated or a previous source has set it.
+	StructDefault bool
+	// SourceKind is the kind of the source that has been searched.
+	SourceKind SourceKind
+	// Path is the path of the file, if SourceKind is SourceKindFile. For
+	// sources reading multiple files, it is the file the value has been read
+	// from.
+	Path string
+	// Line is the line the key has been found in, starting at 1. It is 0 for
+	// the environment and for keys that haven't been found.
+	Line int
+	// Secret is true if the field or any struct containing it is marked via
+	// `secret:"true"`.
+	Secret bool
+	// Value is the value of the field after parsing, formatted the same way
+	// Marshal formats it. Values Marshal can't handle are formatted via fmt.
+	// It isn't redacted, so be careful where you log it. Nil if the value is
+	// a nil pointer.
+	Value *string
+}
+
+// origin describes where the value came from in a human readable way.
+func (p Provenance) origin() string {
+	switch {
+	case p.Default:
+		return "default tag"
+	case p.StructDefault:
+		return "struct default"
+	case !p.Found:
+		return "not found"
+	case p.SourceKind == SourceKindFile:
+		return fmt.Sprintf("file %s:%d", p.Path, p.Line)
+	case p.Line > 0:
+		return fmt.Sprintf("%s line %d", p.SourceKind, p.Line)
+	}
+	return string(p.SourceKind)
+}
+
+// Report collects the Provenance of each field during parsing. If the same
+// report is passed to multiple sources, for example in a yagcl pipeline, a
+// later source only replaces the entry of a field if it set its value. A
+// report mustn't be used by multiple calls to Parse at the same time.
+type Report struct {
+	// Fields holds one entry per field with a value of its own, in the order
+	// of the struct fields.
+	Fields []Provenance
+
+	// indices maps each key to its entry in Fields. It is initialised
+	// lazily, as reports are usually created as zero values.
+	indices map[string]int
+}
+
+// index returns the index of the entry for the given key. The index map is
+// rebuilt if Fields has been modified since it was last used.
+func (r *Report) index(key string) (int, bool) {
+	index, ok := r.indices[key]
+	if r.indices == nil || len(r.indices) != len(r.Fields) || (ok && r.Fields[index].Key != key) {
+		r.indices = make(map[string]int, len(r.Fields))
+		for index, provenance := range r.Fields {
+			r.indices[provenance.Key] = index
+		}
+		index, ok = r.indices[key]
+	}
+	return index, ok
+}
+
+// Lookup returns the entry for the given fully joined key.
+func (r *Report) Lookup(key string) (Provenance, bool) {
+	if index, ok := r.index(key); ok {
+		return r.Fields[index], true
+	}
+	return Provenance{}, false
+}
+
+// record adds the given entry, replacing an existing entry for the same key,
+// unless the new entry didn't set a value.
+func (r *Report) record(provenance Provenance) {
+	if index, ok := r.index(provenance.Key); ok {
+		if provenance.Found || provenance.Default {
+			r.Fields[index] = provenance
+		} else {
+			// The value may have been modified after the source of the
+			// existing entry has been parsed.
+			r.Fields[index].Value = provenance.Value
+		}
+		return
+	}
+	r.indices[provenance.Key] = len(r.Fields)
+	r.Fields = append(r.Fields, provenance)
+}
+
+// WriteTo writes the report as a table, containing the field, key, value and
+// origin of each entry. Values of secret fields are replaced by
+// RedactedValue.
+func (r *Report) WriteTo(writer io.Writer) (int64, error) {
+	var buffer bytes.Buffer
+	tableWriter := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tableWriter, "FIELD\tKEY\tVALUE\tORIGIN")
+	for _, provenance := range r.Fields {
+		var value string
+		if provenance.Secret {
+			value = RedactedValue
+		} else if provenance.Value != nil {
+			// Quoting makes sure that the table isn't broken by newlines.
+			value = quoteValue(*provenance.Value)
+		}
+		fmt.Fprintf(tableWriter, "%s\t%s\t%s\t%s\n", provenance.Field, provenance.Key, value, provenance.origin())
+	}
+	if err := tableWriter.Flush(); err != nil {
+		return 0, err
+	}
+	return buffer.WriteTo(writer)
+}
+
+// String returns the same table as WriteTo.
+func (r *Report) String() string {
+	var builder strings.Builder
+	// Writing to a strings.Builder can't fail.
+	_, _ = r.WriteTo(&builder)
+	return builder.String()
+}
+
+// sourceKind returns the kind of data source the source has been configured
+// with.
+func (s *envSourceImpl) sourceKind() SourceKind {
+	switch {
+	case s.readEnv:
+		return SourceKindEnvironment
+	case s.path != "" || len(s.patterns) > 0:
+		return SourceKindFile
+	case s.reader != nil:
+		return SourceKindReader
+	}
+	return SourceKindBytes
+}
+
+// recordProvenance adds the entry for a field without nested plan to the
+// report of the current parse.
+func (s *envSourceImpl) recordProvenance(state *parseState, field *fieldPlan, value reflect.Value, found, defaulted bool) {
+	provenance := Provenance{
+		Field:         field.path,
+		Key:           field.joinedEnvKey,
+		Found:         found,
+		Default:       defaulted,
+		StructDefault: !found && !defaulted && !value.IsZero(),
+		SourceKind:    s.sourceKind(),
+		Path:          s.path,
+		Secret:        field.secret,
+	}
+	if definition, ok := state.definitions[field.joinedEnvKey]; found && ok {
+		provenance.Line = definition.line
+		if definition.path != "" {
+			provenance.Path = definition.path
+		}
+	}
+	if dereferenced, ok := dereference(value); ok {
+		// Reporting mustn't change the outcome of parsing, so values that
+		// can't be formatted, such as types only implementing
+		// encoding.TextUnmarshaler, are reported in Go syntax instead.
+		formatted, err := formatValue(field.structField.Name, value, field.byteEncoding)
+		if err != nil {
+			if dereferenced.CanInterface() {
+				formatted = fmt.Sprint(dereferenced.Interface())
+			} else {
+				formatted = unformattableValue
+			}
+		}
+		provenance.Value = &formatted
+	}
+
+	s.report.record(provenance)
+}
+
+// unformattableValue is reported for values that can neither be formatted
+// via formatValue nor via fmt.
+const unformattableValue = "<unformattable>"
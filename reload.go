@@ -0,0 +1,129 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrNoReloadSignals is returned by ReloadOnSignal if no signals have been
+// given on platforms without a default signal, such as js.
+var ErrNoReloadSignals = errors.New("no signals given and no default signal available on this platform")
+
+// Reloader holds a configuration, that is re-parsed whenever one of the
+// configured signals is received. Reading the configuration is lock-free and
+// safe for concurrent use.
+type Reloader[T any] struct {
+	parse func() (T, error)
+	// current holds a *T. Since the pointed to configuration is never
+	// modified, it can be shared without copying.
+	current atomic.Value
+
+	// reloadMutex makes sure that reloads don't overlap, so that listeners
+	// are called in order.
+	reloadMutex    sync.Mutex
+	listenersMutex sync.Mutex
+	listeners      []func(Reload[T])
+
+	signals chan os.Signal
+	done    chan struct{}
+	stopped chan struct{}
+	stop    sync.Once
+}
+
+// ReloadOnSignal parses the configuration using the given function and
+// calls it again each time one of the given signals is received. If no
+// signals are given, SIGHUP is used, if the platform has it. Otherwise
+// ErrNoReloadSignals is returned. If reloading fails, the previous
+// configuration is kept and the error is passed to the listeners.
+//
+//	reloader, err := env.ReloadOnSignal(func() (Config, error) {
+//		return env.Parse[Config](env.WithPath(".env"))
+//	})
+func ReloadOnSignal[T any](parse func() (T, error), signals ...os.Signal) (*Reloader[T], error) {
+	if len(signals) == 0 {
+		if len(defaultReloadSignals) == 0 {
+			return nil, ErrNoReloadSignals
+		}
+		signals = defaultReloadSignals
+	}
+
+	initial, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reloader[T]{
+		parse:   parse,
+		signals: make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	r.current.Store(&initial)
+	signal.Notify(r.signals, signals...)
+
+	go r.run()
+	return r, nil
+}
+
+// Load returns the current configuration. It mustn't be modified, as it is
+// shared with all other callers.
+func (r *Reloader[T]) Load() *T {
+	return r.current.Load().(*T)
+}
+
+// OnReload registers a listener, that is called after each reload, no matter
+// whether it was successful.
+func (r *Reloader[T]) OnReload(listener func(Reload[T])) {
+	r.listenersMutex.Lock()
+	defer r.listenersMutex.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+// Reload re-parses the configuration immediately, just like receiving a
+// signal would. The error is also passed to the listeners.
+func (r *Reloader[T]) Reload() error {
+	r.reloadMutex.Lock()
+	defer r.reloadMutex.Unlock()
+
+	reload := Reload[T]{Old: *r.Load()}
+	reload.New, reload.Err = r.parse()
+	if reload.Err == nil {
+		newValue := reload.New
+		r.current.Store(&newValue)
+	}
+
+	r.listenersMutex.Lock()
+	listeners := append([]func(Reload[T]){}, r.listeners...)
+	r.listenersMutex.Unlock()
+	for _, listener := range listeners {
+		listener(reload)
+	}
+
+	return reload.Err
+}
+
+// Stop stops listening for signals. The last configuration stays available.
+func (r *Reloader[T]) Stop() {
+	r.stop.Do(func() {
+		signal.Stop(r.signals)
+		close(r.done)
+		<-r.stopped
+	})
+}
+
+func (r *Reloader[T]) run() {
+	defer close(r.stopped)
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-r.signals:
+			// Errors are reported to the listeners.
+			_ = r.Reload()
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build js
+
+package env
+
+import "os"
+
+// defaultReloadSignals is empty, as there are no signals on this platform,
+// so they have to be passed to ReloadOnSignal explicitly.
+var defaultReloadSignals []os.Signal
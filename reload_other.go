@@ -0,0 +1,11 @@
+//go:build !js
+
+package env
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultReloadSignals are used by ReloadOnSignal if no signals are given.
+var defaultReloadSignals = []os.Signal{syscall.SIGHUP}
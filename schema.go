@@ -0,0 +1,252 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// jsonSchemaDraft is the JSON Schema version produced by WriteJSONSchema.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+type jsonSchema struct {
+	Schema               string                         `json:"$schema"`
+	Type                 string                         `json:"type"`
+	Properties           map[string]*jsonSchemaProperty `json:"properties"`
+	PatternProperties    map[string]bool                `json:"patternProperties,omitempty"`
+	AdditionalProperties bool                           `json:"additionalProperties"`
+	Required             []string                       `json:"required,omitempty"`
+}
+
+type jsonSchemaProperty struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Default     *string  `json:"default,omitempty"`
+	WriteOnly   bool     `json:"writeOnly,omitempty"`
+}
+
+// WriteJSONSchema writes a JSON Schema (draft 2020-12) describing the flat
+// key space of the given configuration struct. Each key is a string property
+// with a pattern matching the syntax accepted for its type, where possible.
+// Keys required via the `env` tag, or via the `required` tag without a
+// default, are listed as required.
+//
+// If a prefix has been configured, unknown keys starting with the prefix
+// are rejected, while all other keys are allowed. Without a prefix, no
+// unknown keys are allowed at all.
+func WriteJSONSchema(writer io.Writer, configurationStruct any, opts ...Option) error {
+	o := newOptions(opts)
+
+	structValue := reflect.Indirect(reflect.ValueOf(configurationStruct))
+	if structValue.Kind() != reflect.Struct {
+		return yagcl.ErrInvalidConfiguraionPointer
+	}
+
+	schema := jsonSchema{
+		Schema:     jsonSchemaDraft,
+		Type:       "object",
+		Properties: make(map[string]*jsonSchemaProperty),
+	}
+	if o.source.prefix != "" {
+		// Since additionalProperties only applies to properties that aren't
+		// matched by patternProperties, we explicitly allow all keys without
+		// the prefix.
+		schema.PatternProperties = map[string]bool{
+			"^(?!" + regexp.QuoteMeta(o.source.keyJoiner(o.source.prefix, "")) + ")": true,
+		}
+	}
+
+	err := o.walk(structValue, func(field walkedField) error {
+		variable, err := describeField(field)
+		if err != nil {
+			return err
+		}
+
+		property := &jsonSchemaProperty{
+			Type:        "string",
+			Description: variable.Description,
+			WriteOnly:   variable.Secret,
+		}
+		if !variable.Secret {
+			property.Default = variable.Default
+		}
+
+		ignoreCase := strings.EqualFold(field.structField.Tag.Get(ignoreCaseTagName), "true")
+		if variable.AllowedValues != nil && !ignoreCase && !isListType(field.underlyingType, field.byteEncoding) {
+			property.Enum = variable.AllowedValues
+		} else if pattern := typePattern(field.underlyingType, field.byteEncoding, variable.AllowedValues, ignoreCase); pattern != "" {
+			property.Pattern = "^" + pattern + "$"
+		}
+
+		schema.Properties[variable.Key] = property
+		if field.options.required || (variable.Required && variable.Default == nil) {
+			schema.Required = append(schema.Required, variable.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(schema)
+}
+
+func isListType(fieldType reflect.Type, byteEncoding string) bool {
+	if isBinary(fieldType, byteEncoding) || reflect.PointerTo(fieldType).Implements(textUnmarshalerType) {
+		return false
+	}
+	kind := fieldType.Kind()
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
+
+const (
+	// mapPartPattern matches a single character of a key or value of a map
+	// entry. Since entries are unescaped before being split at equal signs,
+	// a backslash escaped via another backslash escapes the next character,
+	// while a single backslash only escapes the next character for splitting
+	// at commas, so that "\=" still separates key and value.
+	mapPartPattern = `(?:[^,=\\]|\\[^\\=]|\\\\(?:[^,\\]|\\.))`
+	// mapSeparatorPattern matches the separator between key and value.
+	mapSeparatorPattern = `\\?=`
+	// durationPattern matches what time.ParseDuration accepts.
+	durationPattern = `[+-]?(?:0|(?:(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)(?:ns|us|µs|μs|ms|s|m|h))+)`
+	// floatPattern matches what codec.ParseFloat accepts, which are JSON
+	// numbers and null, optionally surrounded by whitespace.
+	floatPattern = `[ \t\r\n]*(?:null|-?(?:0|[1-9][0-9]*)(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?)[ \t\r\n]*`
+)
+
+// typePattern returns an unanchored ECMA 262 compatible regular expression
+// matching the syntax accepted for the given type. If no restrictions apply,
+// an empty string is returned. If allowedValues is non-nil, values are
+// restricted to those.
+func typePattern(fieldType reflect.Type, byteEncoding string, allowedValues []string, ignoreCase bool) string {
+	fieldType = extractNonPointerFieldType(fieldType)
+	if reflect.PointerTo(fieldType).Implements(textUnmarshalerType) {
+		return alternativesPattern(allowedValues, ignoreCase)
+	}
+
+	if isBinary(fieldType, byteEncoding) {
+		switch byteEncoding {
+		case ByteEncodingHex:
+			if fieldType.Kind() == reflect.Array {
+				return fmt.Sprintf("[0-9a-fA-F]{%d}", fieldType.Len()*2)
+			}
+			return "(?:[0-9a-fA-F]{2})*"
+		case ByteEncodingBase64:
+			return "(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?"
+		case ByteEncodingBase64URL:
+			return "[A-Za-z0-9_-]*=*"
+		}
+		return ""
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Slice, reflect.Array:
+		// Since elements may be escaped, the allowed values have to be
+		// escaped the same way.
+		elementPattern := typePattern(fieldType.Elem(), byteEncoding, escapeAll(allowedValues, ','), ignoreCase)
+		if elementPattern == "" {
+			return ""
+		}
+		if fieldType.Kind() == reflect.Array {
+			if fieldType.Len() == 0 {
+				return ""
+			}
+			return fmt.Sprintf("(?:%s)(?:,(?:%s)){%d},?", elementPattern, elementPattern, fieldType.Len()-1)
+		}
+		// A trailing separator only terminates the last element.
+		return fmt.Sprintf("(?:(?:%s)(?:,(?:%s))*,?)?", elementPattern, elementPattern)
+	case reflect.Map:
+		// Entries are split at commas first and at equal signs second, so
+		// allowed values have to be escaped in the opposite order.
+		keyPattern := typePattern(fieldType.Key(), byteEncoding, nil, false)
+		valuePattern := typePattern(fieldType.Elem(), byteEncoding, escapeAll(escapeAll(allowedValues, '='), ','), ignoreCase)
+		if keyPattern == "" {
+			keyPattern = mapPartPattern + "*"
+		}
+		if valuePattern == "" {
+			// An empty value has to be followed by another equal sign, as a
+			// trailing separator only terminates the last part.
+			valuePattern = mapPartPattern + "+|" + mapSeparatorPattern
+		}
+		entryPattern := fmt.Sprintf("(?:%s)%s(?:%s)", keyPattern, mapSeparatorPattern, valuePattern)
+		return fmt.Sprintf("(?:%s(?:,%s)*,?)?", entryPattern, entryPattern)
+	}
+
+	if allowedValues != nil {
+		return alternativesPattern(allowedValues, ignoreCase)
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int64:
+		if fieldType.AssignableTo(reflect.TypeOf(time.Duration(0))) {
+			return durationPattern
+		}
+		return "[+-]?[0-9]+"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return "[+-]?[0-9]+"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "[0-9]+"
+	case reflect.Float32, reflect.Float64:
+		return floatPattern
+	case reflect.Bool:
+		return "(?:" + caseInsensitivePattern("true") + "|" + caseInsensitivePattern("false") + ")"
+	}
+	return ""
+}
+
+// escapeAll escapes the given values using escapeString. Nil stays nil, as
+// it means that there are no restrictions.
+func escapeAll(values []string, reservedChar rune) []string {
+	if values == nil {
+		return nil
+	}
+	escaped := make([]string, 0, len(values))
+	for _, value := range values {
+		escaped = append(escaped, escapeString(value, reservedChar))
+	}
+	return escaped
+}
+
+// alternativesPattern creates a pattern matching exactly one of the given
+// values. Since ECMA 262 doesn't support inline flags, case-insensitivity is
+// achieved via character classes.
+func alternativesPattern(values []string, ignoreCase bool) string {
+	if values == nil {
+		return ""
+	}
+
+	alternatives := make([]string, 0, len(values))
+	for _, value := range values {
+		if ignoreCase {
+			alternatives = append(alternatives, caseInsensitivePattern(value))
+		} else {
+			alternatives = append(alternatives, regexp.QuoteMeta(value))
+		}
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")"
+}
+
+func caseInsensitivePattern(value string) string {
+	var builder strings.Builder
+	for _, character := range value {
+		lower, upper := strings.ToLower(string(character)), strings.ToUpper(string(character))
+		if lower == upper {
+			builder.WriteString(regexp.QuoteMeta(string(character)))
+		} else {
+			builder.WriteString("[" + upper + lower + "]")
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,188 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type benchmarkGroup struct {
+	Host     string            `key:"host" pattern:"^[a-z.]+$"`
+	Port     uint16            `key:"port" min:"1"`
+	Enabled  bool              `key:"enabled"`
+	Timeout  time.Duration     `key:"timeout" default:"5s"`
+	Ratio    *float64          `key:"ratio"`
+	Tags     []string          `key:"tags"`
+	Labels   map[string]string `key:"labels"`
+	Mode     string            `key:"mode" oneof:"fast,slow"`
+	Retries  int               `key:"retries"`
+	Optional *string           `key:"optional"`
+	Inner    struct {
+		Name  string `key:"name"`
+		Limit int    `key:"limit"`
+	} `key:"inner"`
+}
+
+type benchmarkConfiguration struct {
+	Database benchmarkGroup  `key:"database"`
+	Cache    benchmarkGroup  `key:"cache"`
+	Queue    *benchmarkGroup `key:"queue"`
+	Search   benchmarkGroup  `key:"search"`
+	Metrics  *benchmarkGroup `key:"metrics"`
+	Auth     benchmarkGroup  `key:"auth"`
+}
+
+func benchmarkEnvFile() []byte {
+	var builder strings.Builder
+	for _, group := range []string{"DATABASE", "CACHE", "QUEUE", "SEARCH", "METRICS", "AUTH"} {
+		fmt.Fprintf(&builder, "%s_HOST=%s.example.com\n", group, strings.ToLower(group))
+		fmt.Fprintf(&builder, "%s_PORT=8080\n", group)
+		fmt.Fprintf(&builder, "%s_ENABLED=true\n", group)
+		fmt.Fprintf(&builder, "%s_RATIO=0.5\n", group)
+		fmt.Fprintf(&builder, "%s_TAGS=a,b,c\n", group)
+		fmt.Fprintf(&builder, "%s_LABELS=a=b,c=d\n", group)
+		fmt.Fprintf(&builder, "%s_MODE=fast\n", group)
+		fmt.Fprintf(&builder, "%s_RETRIES=3\n", group)
+		fmt.Fprintf(&builder, "%s_INNER_NAME=inner\n", group)
+		fmt.Fprintf(&builder, "%s_INNER_LIMIT=10\n", group)
+	}
+	return []byte(builder.String())
+}
+
+// Benchmark_Parse_Cold creates a new yagcl instance for each parse. Since
+// its yagcl.ParsingCompanion is part of the cache key, the decoding plan is
+// compiled every time.
+func Benchmark_Parse_Cold(b *testing.B) {
+	data := benchmarkEnvFile()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var c benchmarkConfiguration
+		if err := yagcl.New[benchmarkConfiguration]().Add(env.Source().Bytes(data)).Parse(&c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark_Parse_Warm reuses the source and the yagcl instance, so that
+// the decoding plan is only compiled once.
+func Benchmark_Parse_Warm(b *testing.B) {
+	instance := yagcl.New[benchmarkConfiguration]().Add(env.Source().Bytes(benchmarkEnvFile()))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var c benchmarkConfiguration
+		if err := instance.Parse(&c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark_Parse_Warm_Function uses env.Parse, which creates a new source
+// and parsing companion for each call, but still hits the shared cache.
+func Benchmark_Parse_Warm_Function(b *testing.B) {
+	data := benchmarkEnvFile()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := env.Parse[benchmarkConfiguration](env.WithBytes(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Test_Parse_ReusedSource_Concurrent(t *testing.T) {
+	instance := yagcl.New[benchmarkConfiguration]().Add(env.Source().Bytes(benchmarkEnvFile()))
+
+	var expected benchmarkConfiguration
+	if !assert.NoError(t, instance.Parse(&expected)) {
+		return
+	}
+	if assert.NotNil(t, expected.Queue) {
+		assert.Equal(t, "queue.example.com", expected.Queue.Host)
+		assert.Equal(t, 5*time.Second, expected.Queue.Timeout)
+		assert.Equal(t, 10, expected.Queue.Inner.Limit)
+	}
+
+	var wait sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			var c benchmarkConfiguration
+			if assert.NoError(t, instance.Parse(&c)) {
+				assert.Equal(t, expected, c)
+			}
+		}()
+	}
+	wait.Wait()
+}
+
+func Test_Parse_ReusedSource_PrefixChanged(t *testing.T) {
+	type configuration struct {
+		Host string `key:"host"`
+	}
+
+	source := env.Source().String("A_HOST=a\nB_HOST=b")
+	instance := yagcl.New[configuration]().Add(source)
+
+	var c configuration
+	source.Prefix("A")
+	if assert.NoError(t, instance.Parse(&c)) {
+		assert.Equal(t, "a", c.Host)
+	}
+
+	c = configuration{}
+	source.Prefix("B")
+	if assert.NoError(t, instance.Parse(&c)) {
+		assert.Equal(t, "b", c.Host)
+	}
+}
+
+func Test_Parse_KeyValueConverterClosures(t *testing.T) {
+	type configuration struct {
+		Host string `key:"host"`
+	}
+
+	// Closures created by the same function share their code, but produce
+	// different keys, so they mustn't share a decoding plan.
+	withSuffix := func(suffix string) func(string) string {
+		return func(key string) string {
+			return strings.ToUpper(key) + suffix
+		}
+	}
+
+	for suffix, expected := range map[string]string{"_A": "a", "_B": "b"} {
+		var c configuration
+		source := env.Source().String("HOST_A=a\nHOST_B=b").KeyValueConverter(withSuffix(suffix))
+		if assert.NoError(t, yagcl.New[configuration]().Add(source).Parse(&c)) {
+			assert.Equal(t, expected, c.Host)
+		}
+	}
+}
+
+func Test_Parse_ReusedSource_KeyValueConverterChanged(t *testing.T) {
+	type configuration struct {
+		Host string `key:"host"`
+	}
+
+	source := env.Source().String("HOST=upper\nhost=lower")
+	instance := yagcl.New[configuration]().Add(source)
+
+	var c configuration
+	if assert.NoError(t, instance.Parse(&c)) {
+		assert.Equal(t, "upper", c.Host)
+	}
+
+	c = configuration{}
+	source.KeyValueConverter(strings.ToLower)
+	if assert.NoError(t, instance.Parse(&c)) {
+		assert.Equal(t, "lower", c.Host)
+	}
+}
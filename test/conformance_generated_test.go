@@ -0,0 +1,335 @@
+// Code generated by yagcl-env. DO NOT EDIT.
+
+//go:build !yagclenv_generate
+
+package env
+
+import "github.com/Bios-Marcel/yagcl-env/envgen"
+
+// parseConformanceEnv parses conformanceConfiguration from the given lookup, such as os.LookupEnv.
+func parseConformanceEnv(lookup func(string) (string, bool)) (conformanceConfiguration, error) {
+	var c conformanceConfiguration
+	err := parseConformanceEnvInto(lookup, &c)
+	return c, err
+}
+
+// parseConformanceEnvInto parses conformanceConfiguration from the given lookup, such as
+// os.LookupEnv, preserving all values that have been set beforehand.
+func parseConformanceEnvInto(lookup func(string) (string, bool), c *conformanceConfiguration) error {
+	var missing []string
+	if value, ok := lookup("CONF_STRING"); ok {
+		v := envgen.ZeroValue(c.String)
+		if err := envgen.ParseString(value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.String = v
+		}
+	}
+	if value, ok := lookup("CONF_ALIAS"); ok {
+		v := envgen.ZeroValue(c.Alias)
+		if err := envgen.ParseString(value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Alias = v
+		}
+	}
+	if value, ok := lookup("CONF_INT"); ok {
+		v := envgen.ZeroValue(c.Int)
+		if err := envgen.ParseInt("Int", "int", value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Int = v
+		}
+	}
+	if value, ok := lookup("CONF_INT8"); ok {
+		v := envgen.ZeroValue(c.Int8)
+		if err := envgen.ParseInt("Int8", "int8", value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Int8 = v
+		}
+	}
+	if value, ok := lookup("CONF_UINT16"); ok {
+		v := envgen.ZeroValue(c.Uint16)
+		if err := envgen.ParseUint("Uint16", "uint16", value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Uint16 = v
+		}
+	}
+	if value, ok := lookup("CONF_FLOAT32"); ok {
+		v := envgen.ZeroValue(c.Float32)
+		if err := envgen.ParseFloat("Float32", "float32", value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Float32 = v
+		}
+	}
+	if value, ok := lookup("CONF_FLOAT64"); ok {
+		v := envgen.ZeroElem(c.Float64)
+		if err := envgen.ParseFloat("Float64", "float64", value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Float64 = envgen.Pointer(v)
+		}
+	}
+	if value, ok := lookup("CONF_BOOL"); ok {
+		v := envgen.ZeroValue(c.Bool)
+		if err := envgen.ParseBool("Bool", "bool", value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Bool = v
+		}
+	}
+	{
+		set := func(value string) error {
+			v := envgen.ZeroValue(c.Duration)
+			if err := envgen.ParseDuration("Duration", value, &v); err != nil {
+				return err
+			}
+			if !envgen.IsZero(v) {
+				c.Duration = v
+			}
+			return nil
+		}
+		if value, ok := lookup("CONF_DURATION"); ok {
+			if err := set(value); err != nil {
+				return err
+			}
+		} else if envgen.IsZero(c.Duration) {
+			if err := set("5s"); err != nil {
+				return envgen.InvalidDefault("Duration", err)
+			}
+		}
+	}
+	if value, ok := lookup("CONF_DOUBLE_POINTER"); ok {
+		v := envgen.ZeroElem(envgen.ZeroElem(c.DoublePointer))
+		if err := envgen.ParseInt("DoublePointer", "int", value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.DoublePointer = envgen.Pointer(envgen.Pointer(v))
+		}
+	}
+	if value, ok := lookup("CONF_CUSTOM"); ok {
+		target := envgen.Pointer(c.Custom)
+		if err := envgen.UnmarshalText("Custom", "env.customTextUnmarshalable", value, target); err != nil {
+			return err
+		}
+		c.Custom = *target
+	}
+	if value, ok := lookup("CONF_CUSTOM_POINTER"); ok {
+		target := envgen.New(envgen.ZeroValue(c.CustomPointer))
+		if c.CustomPointer != nil {
+			target = c.CustomPointer
+		}
+		if err := envgen.UnmarshalText("CustomPointer", "env.intCustomUnmarshalable", value, target); err != nil {
+			return err
+		}
+		c.CustomPointer = envgen.Pointer(*target)
+	}
+	if value, ok := lookup("CONF_BYTES"); ok {
+		v := envgen.ZeroValue(c.Bytes)
+		if err := envgen.ParseBytes("Bytes", "base64", value, &v); err != nil {
+			return err
+		}
+		if v != nil {
+			c.Bytes = v
+		}
+	}
+	if value, ok := lookup("CONF_KEY"); ok {
+		v := envgen.ZeroValue(c.Key)
+		if err := envgen.ParseByteArray("Key", "hex", value, v[:]); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Key = v
+		}
+	}
+	if value, ok := lookup("CONF_STRINGS"); ok {
+		v := envgen.ZeroValue(c.Strings)
+		parts := envgen.Split(value, ',')
+		v = envgen.MakeSlice(v, len(parts))
+		for i, part := range parts {
+			e := envgen.ZeroValue(v[i])
+			if err := envgen.ParseString(part, &e); err != nil {
+				return err
+			}
+			v[i] = e
+		}
+		if v != nil {
+			c.Strings = v
+		}
+	}
+	if value, ok := lookup("CONF_INTS"); ok {
+		v := envgen.ZeroValue(c.Ints)
+		parts := envgen.Split(value, ',')
+		v = envgen.MakeSlice(v, len(parts))
+		for i, part := range parts {
+			e := envgen.ZeroElem(v[i])
+			if err := envgen.ParseInt("Ints", "int", part, &e); err != nil {
+				return err
+			}
+			v[i] = envgen.Pointer(e)
+		}
+		if v != nil {
+			c.Ints = v
+		}
+	}
+	if value, ok := lookup("CONF_ARRAY"); ok {
+		v := envgen.ZeroValue(c.Array)
+		parts := envgen.Split(value, ',')
+		if err := envgen.CheckArrayLength("Array", len(v), len(parts)); err != nil {
+			return err
+		}
+		for i, part := range parts {
+			e := envgen.ZeroValue(v[i])
+			if err := envgen.ParseUint("Array", "uint", part, &e); err != nil {
+				return err
+			}
+			v[i] = e
+		}
+		if !envgen.IsZero(v) {
+			c.Array = v
+		}
+	}
+	if value, ok := lookup("CONF_MAP"); ok {
+		v := envgen.ZeroValue(c.Map)
+		entries := envgen.Split(value, ',')
+		v = envgen.MakeMap(v, len(entries))
+		for index, entry := range entries {
+			rawKey, rawElement, err := envgen.SplitMapEntry("Map", index, entry)
+			if err != nil {
+				return err
+			}
+			key, element := envgen.MapZero(v)
+			if err := envgen.ParseString(rawKey, &key); err != nil {
+				return envgen.InvalidMapKey("Map", rawKey)
+			}
+			if err := envgen.ParseDuration("Map", rawElement, &element); err != nil {
+				return envgen.InvalidMapValue("Map", rawElement)
+			}
+			v[key] = element
+		}
+		if v != nil {
+			c.Map = v
+		}
+	}
+	if value, ok := lookup("CONF_REQUIRED"); ok {
+		v := envgen.ZeroValue(c.Required)
+		if err := envgen.ParseString(value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Required = v
+		}
+	} else {
+		missing = append(missing, "CONF_REQUIRED")
+	}
+	{
+		set := func(value string) error {
+			v := envgen.ZeroElem(c.Default)
+			if err := envgen.ParseString(value, &v); err != nil {
+				return err
+			}
+			if !envgen.IsZero(v) {
+				c.Default = envgen.Pointer(v)
+			}
+			return nil
+		}
+		if value, ok := lookup("CONF_DEFAULT"); ok {
+			if err := set(value); err != nil {
+				return err
+			}
+		} else if c.Default == nil {
+			if err := set("fallback"); err != nil {
+				return envgen.InvalidDefault("Default", err)
+			}
+		}
+	}
+	if value, ok := lookup("CONF_NESTED_NAME"); ok {
+		v := envgen.ZeroValue(c.Nested.Name)
+		if err := envgen.ParseString(value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Nested.Name = v
+		}
+	}
+	if value, ok := lookup("CONF_NESTED_LIMIT"); ok {
+		v := envgen.ZeroElem(c.Nested.Limit)
+		if err := envgen.ParseInt("Limit", "int", value, &v); err != nil {
+			return err
+		}
+		if !envgen.IsZero(v) {
+			c.Nested.Limit = envgen.Pointer(v)
+		}
+	}
+	// NestedPointer
+	{
+		s1 := envgen.New(envgen.ZeroValue(c.NestedPointer))
+		existing := c.NestedPointer != nil
+		if existing {
+			s1 = c.NestedPointer
+		}
+		if value, ok := lookup("CONF_NESTED_POINTER_NAME"); ok {
+			v := envgen.ZeroValue(s1.Name)
+			if err := envgen.ParseString(value, &v); err != nil {
+				return err
+			}
+			if !envgen.IsZero(v) {
+				s1.Name = v
+			}
+		}
+		if value, ok := lookup("CONF_NESTED_POINTER_LIMIT"); ok {
+			v := envgen.ZeroElem(s1.Limit)
+			if err := envgen.ParseInt("Limit", "int", value, &v); err != nil {
+				return err
+			}
+			if !envgen.IsZero(v) {
+				s1.Limit = envgen.Pointer(v)
+			}
+		}
+		if !existing && !envgen.IsZero(*s1) {
+			c.NestedPointer = envgen.Pointer(*s1)
+		}
+	}
+	// DoubleNested
+	{
+		s2 := envgen.New(envgen.ZeroElem(c.DoubleNested))
+		existing := c.DoubleNested != nil && *c.DoubleNested != nil
+		if existing {
+			s2 = *c.DoubleNested
+		}
+		if value, ok := lookup("CONF_DOUBLE_NESTED_NAME"); ok {
+			v := envgen.ZeroValue(s2.Name)
+			if err := envgen.ParseString(value, &v); err != nil {
+				return err
+			}
+			if !envgen.IsZero(v) {
+				s2.Name = v
+			}
+		}
+		if value, ok := lookup("CONF_DOUBLE_NESTED_LIMIT"); ok {
+			v := envgen.ZeroElem(s2.Limit)
+			if err := envgen.ParseInt("Limit", "int", value, &v); err != nil {
+				return err
+			}
+			if !envgen.IsZero(v) {
+				s2.Limit = envgen.Pointer(v)
+			}
+		}
+		if !existing && !envgen.IsZero(*s2) {
+			c.DoubleNested = envgen.Pointer(envgen.Pointer(*s2))
+		}
+	}
+	return envgen.MissingKeys(missing)
+}
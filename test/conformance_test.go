@@ -0,0 +1,218 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+// The conformance tests make sure that parsers generated via WriteParser
+// behave exactly like the reflection based source. In order to update the
+// generated parser, run the tests with UPDATE_GENERATED=true.
+
+const conformanceGeneratedFile = "conformance_generated_test.go"
+
+type conformanceNested struct {
+	Name  string `key:"name"`
+	Limit *int   `key:"limit"`
+}
+
+type conformanceString string
+
+type conformanceConfiguration struct {
+	String        string                   `key:"string"`
+	Alias         conformanceString        `key:"alias"`
+	Int           int                      `key:"int"`
+	Int8          int8                     `key:"int8"`
+	Uint16        uint16                   `key:"uint16"`
+	Float32       float32                  `key:"float32"`
+	Float64       *float64                 `key:"float64"`
+	Bool          bool                     `key:"bool"`
+	Duration      time.Duration            `key:"duration" default:"5s"`
+	DoublePointer **int                    `key:"double_pointer"`
+	Custom        customTextUnmarshalable  `key:"custom"`
+	CustomPointer *intCustomUnmarshalable  `key:"custom_pointer"`
+	Bytes         []byte                   `key:"bytes" encoding:"base64"`
+	Key           [4]byte                  `key:"key" encoding:"hex"`
+	Strings       []string                 `key:"strings"`
+	Ints          []*int                   `key:"ints"`
+	Array         [2]uint                  `key:"array"`
+	Map           map[string]time.Duration `key:"map"`
+	Required      string                   `key:"required" env:",required"`
+	Default       *string                  `key:"default" default:"fallback"`
+	Nested        conformanceNested        `key:"nested"`
+	NestedPointer *conformanceNested       `key:"nested_pointer"`
+	DoubleNested  **conformanceNested      `key:"double_nested"`
+	Ignored       string                   `key:"ignored" ignore:"true"`
+}
+
+func Test_Conformance_GeneratedParserUpToDate(t *testing.T) {
+	var generated bytes.Buffer
+	if !assert.NoError(t, env.WriteParser(&generated, &conformanceConfiguration{}, "env", "parseConformanceEnv", env.WithPrefix("CONF"))) {
+		return
+	}
+
+	if os.Getenv("UPDATE_GENERATED") == "true" {
+		assert.NoError(t, os.WriteFile(conformanceGeneratedFile, generated.Bytes(), 0o644))
+		return
+	}
+
+	existing, err := os.ReadFile(conformanceGeneratedFile)
+	if assert.NoError(t, err) {
+		assert.Equal(t, generated.String(), string(existing), "generated parser is outdated, run the tests with UPDATE_GENERATED=true")
+	}
+}
+
+func Test_Conformance(t *testing.T) {
+	pointerTo := func(i int) *int { return &i }
+	presets := func() conformanceConfiguration {
+		limit := 5
+		duration := time.Minute
+		return conformanceConfiguration{
+			Int:           7,
+			Custom:        "PRESET",
+			Duration:      duration,
+			NestedPointer: &conformanceNested{Name: "preset", Limit: &limit},
+			Ignored:       "ignored",
+			Ints:          []*int{pointerTo(1)},
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		env     map[string]string
+		initial func() conformanceConfiguration
+	}{
+		{
+			name: "all set",
+			env: map[string]string{
+				"CONF_STRING":                "a\\,b",
+				"CONF_ALIAS":                 "alias",
+				"CONF_INT":                   "-42",
+				"CONF_INT8":                  "127",
+				"CONF_UINT16":                "65535",
+				"CONF_FLOAT32":               "1.5e3",
+				"CONF_FLOAT64":               " -0.25 ",
+				"CONF_BOOL":                  "TRUE",
+				"CONF_DURATION":              "1h30m",
+				"CONF_DOUBLE_POINTER":        "3",
+				"CONF_CUSTOM":                "lower",
+				"CONF_CUSTOM_POINTER":        "12",
+				"CONF_BYTES":                 "aGVsbG8=",
+				"CONF_KEY":                   "deadbeef",
+				"CONF_STRINGS":               "a,b\\,c,ö",
+				"CONF_INTS":                  "1,0,-1",
+				"CONF_ARRAY":                 "1,2",
+				"CONF_MAP":                   "a=1s,b\\=c=2m",
+				"CONF_REQUIRED":              "required",
+				"CONF_DEFAULT":               "set",
+				"CONF_NESTED_NAME":           "nested",
+				"CONF_NESTED_LIMIT":          "1",
+				"CONF_NESTED_POINTER_NAME":   "pointer",
+				"CONF_DOUBLE_NESTED_LIMIT":   "2",
+				"CONF_IGNORED":               "value",
+				"CONF_NESTED_POINTER_UNUSED": "value",
+			},
+		},
+		{
+			name: "defaults",
+			env:  map[string]string{"CONF_REQUIRED": "required"},
+		},
+		{
+			name:    "presets preserved",
+			env:     map[string]string{"CONF_REQUIRED": "required", "CONF_INT": "0", "CONF_NESTED_POINTER_NAME": "overwritten"},
+			initial: presets,
+		},
+		{
+			name:    "presets overwritten",
+			env:     map[string]string{"CONF_INT": "1", "CONF_CUSTOM": "new", "CONF_DURATION": "1s", "CONF_INTS": "", "CONF_NESTED_POINTER_LIMIT": "2"},
+			initial: presets,
+		},
+		{
+			name: "empty values",
+			env:  map[string]string{"CONF_REQUIRED": "", "CONF_STRING": "", "CONF_STRINGS": "", "CONF_MAP": "", "CONF_BYTES": "", "CONF_FLOAT64": "null", "CONF_DEFAULT": ""},
+		},
+		{
+			name: "zero values",
+			env:  map[string]string{"CONF_REQUIRED": "x", "CONF_DOUBLE_POINTER": "0", "CONF_DOUBLE_NESTED_NAME": "", "CONF_ARRAY": "0,0", "CONF_KEY": "00000000"},
+		},
+		{name: "required missing"},
+		{name: "invalid int", env: map[string]string{"CONF_INT": "1.0"}},
+		{name: "int overflow", env: map[string]string{"CONF_INT8": "128"}},
+		{name: "uint negative", env: map[string]string{"CONF_UINT16": "-1"}},
+		{name: "invalid float", env: map[string]string{"CONF_FLOAT32": "1."}},
+		{name: "float hex", env: map[string]string{"CONF_FLOAT32": "0x10"}},
+		{name: "float out of range", env: map[string]string{"CONF_FLOAT64": "1e400"}},
+		{name: "invalid bool", env: map[string]string{"CONF_BOOL": "yes"}},
+		{name: "invalid duration", env: map[string]string{"CONF_DURATION": "5"}},
+		{name: "invalid double pointer", env: map[string]string{"CONF_DOUBLE_POINTER": "x"}},
+		{name: "invalid custom", env: map[string]string{"CONF_CUSTOM_POINTER": "x"}},
+		{name: "invalid bytes", env: map[string]string{"CONF_BYTES": "!"}},
+		{name: "invalid key length", env: map[string]string{"CONF_KEY": "dead"}},
+		{name: "invalid slice element", env: map[string]string{"CONF_INTS": "1,x"}},
+		{name: "invalid array length", env: map[string]string{"CONF_ARRAY": "1,2,3"}},
+		{
+			name: "trailing separators",
+			env:  map[string]string{"CONF_REQUIRED": "x", "CONF_INTS": "1,2,", "CONF_STRINGS": "a,,", "CONF_MAP": "a=1s,"},
+		},
+		{name: "invalid map entry", env: map[string]string{"CONF_MAP": "a"}},
+		{name: "empty map entry", env: map[string]string{"CONF_MAP": "a=1s,,"}},
+		{name: "ambiguous map entry", env: map[string]string{"CONF_MAP": "a=b=c"}},
+		{name: "invalid map value", env: map[string]string{"CONF_MAP": "a=b"}},
+		{name: "invalid nested", env: map[string]string{"CONF_DOUBLE_NESTED_LIMIT": "x"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			for key, value := range testCase.env {
+				t.Setenv(key, value)
+			}
+			initial := func() conformanceConfiguration { return conformanceConfiguration{} }
+			if testCase.initial != nil {
+				initial = testCase.initial
+			}
+
+			expected := initial()
+			errExpected := yagcl.New[conformanceConfiguration]().
+				Add(env.Source().Env().Prefix("CONF")).
+				Parse(&expected)
+
+			actual := initial()
+			errActual := parseConformanceEnvInto(os.LookupEnv, &actual)
+
+			assert.Equal(t, expected, actual)
+			if errExpected == nil {
+				assert.NoError(t, errActual)
+				return
+			}
+			if assert.Error(t, errActual) {
+				assert.Equal(t, errExpected.Error(), errActual.Error())
+				for _, sentinel := range []error{yagcl.ErrParseValue, env.ErrRequiredKeysMissing} {
+					assert.Equal(t, errors.Is(errExpected, sentinel), errors.Is(errActual, sentinel))
+				}
+			}
+		})
+	}
+}
+
+func Test_WriteParser_ValidationTags(t *testing.T) {
+	type configuration struct {
+		Port int `key:"port" min:"1"`
+	}
+
+	err := env.WriteParser(&bytes.Buffer{}, &configuration{}, "config", "ParseEnv")
+	assert.ErrorIs(t, err, env.ErrNotGeneratable)
+}
+
+func Test_WriteParser_AnonymousStruct(t *testing.T) {
+	err := env.WriteParser(&bytes.Buffer{}, &struct {
+		Host string `key:"host"`
+	}{}, "config", "ParseEnv")
+	assert.ErrorIs(t, err, env.ErrNotGeneratable)
+}
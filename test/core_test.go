@@ -289,3 +289,58 @@ func Test_Parse_CustomKeyJoiner(t *testing.T) {
 		assert.Equal(t, "content a", c.FieldA)
 	}
 }
+
+func Test_Parse_RequiredKeys(t *testing.T) {
+	type configuration struct {
+		DatabaseURL string `env:"DATABASE_URL,required"`
+		Optional    string `key:"optional"`
+		Nested      *struct {
+			Host string `key:"host" env:",required"`
+			Port *int   `env:"PORT,required"`
+		} `key:"nested"`
+	}
+
+	t.Setenv("APP_NESTED_PORT", "8080")
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(env.Source().Env().Prefix("APP")).
+		Parse(&c)
+	if assert.ErrorIs(t, err, env.ErrRequiredKeysMissing) {
+		assert.Contains(t, err.Error(), "[APP_DATABASE_URL, APP_NESTED_HOST]")
+	}
+
+	t.Setenv("APP_DATABASE_URL", "postgres://localhost")
+	t.Setenv("APP_NESTED_HOST", "localhost")
+	err = yagcl.New[configuration]().
+		Add(env.Source().Env().Prefix("APP")).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "postgres://localhost", c.DatabaseURL)
+		assert.Equal(t, "localhost", c.Nested.Host)
+		assert.Equal(t, 8080, *c.Nested.Port)
+	}
+}
+
+func Test_Parse_AmbiguousRequired(t *testing.T) {
+	type configuration struct {
+		Nested struct {
+			Host string `env:"HOST,required" required:"true"`
+		} `key:"nested"`
+	}
+
+	t.Setenv("NESTED_HOST", "localhost")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.ErrorIs(t, err, env.ErrAmbiguousRequired)
+	assert.EqualError(t, err, "field 'Host': "+env.ErrAmbiguousRequired.Error())
+}
+
+func Test_Parse_UnknownKeyTagOption(t *testing.T) {
+	type configuration struct {
+		FieldA string `env:"FIELD_A,requierd"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.Error(t, err)
+}
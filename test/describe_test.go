@@ -0,0 +1,74 @@
+package env
+
+import (
+	"bytes"
+	"testing"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type describeConfiguration struct {
+	LogLevel string            `key:"log_level" oneof:"debug,info" desc:"Log level | verbosity"`
+	Hosts    []string          `key:"hosts" default:"a,b"`
+	Labels   map[string]string `key:"labels"`
+	Key      []byte            `key:"key" encoding:"hex"`
+	Password string            `key:"password" secret:"true"`
+	Database *struct {
+		URL string `env:"URL,required"`
+	} `key:"database"`
+}
+
+func Test_Describe(t *testing.T) {
+	c := describeConfiguration{Password: "hunter2"}
+	variables, err := env.Describe(&c, env.WithPrefix("APP"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defaultHosts := "a,b"
+	redacted := env.RedactedValue
+	assert.Equal(t, env.Variables{
+		{Key: "APP_LOG_LEVEL", Type: "string", AllowedValues: []string{"debug", "info"}, Description: "Log level | verbosity"},
+		{Key: "APP_HOSTS", Type: "[]string", Separator: ",", Default: &defaultHosts},
+		{Key: "APP_LABELS", Type: "map[string]string", Separator: ",", KeyValueSeparator: "="},
+		{Key: "APP_KEY", Type: "[]uint8"},
+		{Key: "APP_PASSWORD", Type: "string", Secret: true, Default: &redacted},
+		{Key: "APP_DATABASE_URL", Type: "string", Required: true},
+	}, variables)
+}
+
+func Test_Describe_JSON(t *testing.T) {
+	type configuration struct {
+		Port int `key:"port" default:"8080" desc:"Port to listen on"`
+	}
+
+	variables, err := env.Describe(configuration{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var buffer bytes.Buffer
+	if assert.NoError(t, variables.WriteJSON(&buffer)) {
+		assert.JSONEq(t, `[{"key":"PORT","type":"int","default":"8080","required":false,"secret":false,"description":"Port to listen on"}]`, buffer.String())
+	}
+}
+
+func Test_Describe_Markdown(t *testing.T) {
+	variables, err := env.Describe(describeConfiguration{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var buffer bytes.Buffer
+	if assert.NoError(t, variables.WriteMarkdown(&buffer)) {
+		assert.Equal(t, "| Key | Type | Default | Required | Secret | Separator | Allowed values | Description |\n"+
+			"| --- | --- | --- | --- | --- | --- | --- | --- |\n"+
+			"| `LOG_LEVEL` | `string` |  | no | no |  | `debug`, `info` | Log level \\| verbosity |\n"+
+			"| `HOSTS` | `[]string` | `a,b` | no | no | , |  |  |\n"+
+			"| `LABELS` | `map[string]string` |  | no | no | , = |  |  |\n"+
+			"| `KEY` | `[]uint8` |  | no | no |  |  |  |\n"+
+			"| `PASSWORD` | `string` |  | no | yes |  |  |  |\n"+
+			"| `DATABASE_URL` | `string` |  | yes | no |  |  |  |\n", buffer.String())
+	}
+}
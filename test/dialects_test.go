@@ -0,0 +1,83 @@
+package env
+
+import (
+	"errors"
+	"testing"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Dialects(t *testing.T) {
+	t.Setenv("DIALECT_PROCESS", "process")
+	t.Setenv("C", "from process")
+
+	testCases := []struct {
+		name     string
+		dialect  env.Dialect
+		content  string
+		expected dotenvConfiguration
+	}{
+		{name: "dotenv by default", content: "A=\"a\" # comment", expected: dotenvConfiguration{A: "a"}},
+
+		{name: "docker literal values", dialect: env.DialectDocker, content: "A=\"a\" # no comment\n  B= b \r\n", expected: dotenvConfiguration{A: "\"a\" # no comment", B: " b "}},
+		{name: "docker comments", dialect: env.DialectDocker, content: "# A=a\n\t#B=b\n\nA=$DIALECT_PROCESS", expected: dotenvConfiguration{A: "$DIALECT_PROCESS"}},
+		{name: "docker no multiline", dialect: env.DialectDocker, content: "A=\"first\nB=second\"", expected: dotenvConfiguration{A: "\"first", B: "second\""}},
+		{name: "docker inherited", dialect: env.DialectDocker, content: "C\nB\nA=a=b", expected: dotenvConfiguration{A: "a=b", C: "from process"}},
+
+		{name: "systemd quotes", dialect: env.DialectSystemd, content: "A='a \"b\" \\n'\nB=\"a \\\"b\\\" \\$c \\n\"", expected: dotenvConfiguration{A: "a \"b\" \\n", B: "a \"b\" $c \\n"}},
+		{name: "systemd whitespace", dialect: env.DialectSystemd, content: "  A  =  a b  \nB= \" b \" ", expected: dotenvConfiguration{A: "a b", B: " b "}},
+		{name: "systemd comments", dialect: env.DialectSystemd, content: "# A=a\n; B=b\nC=c # no comment", expected: dotenvConfiguration{C: "c # no comment"}},
+		{name: "systemd continuation", dialect: env.DialectSystemd, content: "A=first \\\nsecond\nB=\"multi\nline\"\nC=\"x\\\ny\"", expected: dotenvConfiguration{A: "first second", B: "multi\nline", C: "xy"}},
+		{name: "systemd concatenation", dialect: env.DialectSystemd, content: "A=\"a\" 'b'c\\ d", expected: dotenvConfiguration{A: "abc d"}},
+		{name: "systemd no expansion", dialect: env.DialectSystemd, content: "A=$DIALECT_PROCESS", expected: dotenvConfiguration{A: "$DIALECT_PROCESS"}},
+		{name: "systemd invalid lines ignored", dialect: env.DialectSystemd, content: "invalid line\n1X=a\nA=a\nB", expected: dotenvConfiguration{A: "a"}},
+		{name: "systemd unterminated quotes", dialect: env.DialectSystemd, content: "A=\"a", expected: dotenvConfiguration{A: "a"}},
+
+		{name: "posix quotes", dialect: env.DialectPOSIXShell, content: `A='a "b" $C'"-$C-"\$x\ y`, expected: dotenvConfiguration{A: `a "b" $C-from process-$x y`}},
+		{name: "posix export and comments", dialect: env.DialectPOSIXShell, content: "# comment\nexport A=a # comment\nB=b; C=c", expected: dotenvConfiguration{A: "a", B: "b", C: "c"}},
+		{name: "posix prefers assignments", dialect: env.DialectPOSIXShell, content: "C=assigned\nA=$C\nB=${DIALECT_PROCESS}", expected: dotenvConfiguration{A: "assigned", B: "process", C: "assigned"}},
+		{name: "posix multiline", dialect: env.DialectPOSIXShell, content: "A='multi\nline'\nB=\"x\\\ny\"\nC=first\\\nsecond", expected: dotenvConfiguration{A: "multi\nline", B: "xy", C: "firstsecond"}},
+		{name: "posix backslashes in double quotes", dialect: env.DialectPOSIXShell, content: `A="\a\\b"`, expected: dotenvConfiguration{A: `\a\b`}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c, err := env.Parse[dotenvConfiguration](env.WithBytes([]byte(testCase.content)), env.WithDialect(testCase.dialect))
+			if assert.NoError(t, err) {
+				assert.Equal(t, testCase.expected, c)
+			}
+		})
+	}
+}
+
+func Test_Dialects_SyntaxErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dialect  env.Dialect
+		content  string
+		expected env.SyntaxError
+	}{
+		{name: "docker whitespace in key", dialect: env.DialectDocker, content: "A=a\n  B C=c", expected: env.SyntaxError{Line: 2, Column: 3, Message: "key 'B C' contains whitespace"}},
+		{name: "docker missing key", dialect: env.DialectDocker, content: "=a", expected: env.SyntaxError{Line: 1, Column: 1, Message: "missing key"}},
+		{name: "posix whitespace around delimiter", dialect: env.DialectPOSIXShell, content: "A = a", expected: env.SyntaxError{Line: 1, Column: 2, Message: "expected '=' directly after 'A'"}},
+		{name: "posix command", dialect: env.DialectPOSIXShell, content: "echo hi", expected: env.SyntaxError{Line: 1, Column: 5, Message: "expected '=' directly after 'echo'"}},
+		{name: "posix command after assignment", dialect: env.DialectPOSIXShell, content: "A=a b", expected: env.SyntaxError{Line: 1, Column: 5, Message: "unexpected character 'b' after assignment to 'A'"}},
+		{name: "posix command substitution", dialect: env.DialectPOSIXShell, content: "A=$(pwd)", expected: env.SyntaxError{Line: 1, Column: 3, Message: "unsupported command substitution"}},
+		{name: "posix backticks", dialect: env.DialectPOSIXShell, content: "A=\"`pwd`\"", expected: env.SyntaxError{Line: 1, Column: 4, Message: "unsupported shell syntax '`'"}},
+		{name: "posix unterminated quotes", dialect: env.DialectPOSIXShell, content: "\nA='a", expected: env.SyntaxError{Line: 2, Column: 3, Message: "unterminated single quoted value"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := env.Parse[dotenvConfiguration](env.WithBytes([]byte(testCase.content)), env.WithDialect(testCase.dialect))
+			var syntaxError *env.SyntaxError
+			if assert.True(t, errors.As(err, &syntaxError), "unexpected error: %v", err) {
+				assert.Equal(t, testCase.expected, *syntaxError)
+			}
+		})
+	}
+}
+
+func Test_Dialects_Unsupported(t *testing.T) {
+	_, err := env.Parse[dotenvConfiguration](env.WithBytes([]byte("A=a")), env.WithDialect("toml"))
+	assert.ErrorIs(t, err, env.ErrUnsupportedDialect)
+}
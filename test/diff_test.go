@@ -0,0 +1,87 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type diffConfiguration struct {
+	Host     string            `key:"host"`
+	Timeout  time.Duration     `key:"timeout"`
+	Limit    *int              `key:"limit"`
+	Hosts    []string          `key:"hosts"`
+	Labels   map[string]string `key:"labels"`
+	Password string            `key:"password" secret:"true"`
+	Database *struct {
+		Name string `key:"name"`
+	} `key:"database"`
+}
+
+func Test_Diff(t *testing.T) {
+	limit := 5
+	oldConfiguration := diffConfiguration{
+		Host:     "localhost",
+		Timeout:  time.Second,
+		Limit:    &limit,
+		Hosts:    []string{"a", "b"},
+		Labels:   map[string]string{"a": "1", "b": "2"},
+		Password: "old",
+	}
+	newConfiguration := diffConfiguration{
+		Host:     "localhost",
+		Timeout:  time.Minute,
+		Hosts:    []string{"a", "b", "c"},
+		Labels:   map[string]string{"b": "2", "a": "1"},
+		Password: "new",
+	}
+	newConfiguration.Database = &struct {
+		Name string `key:"name"`
+	}{Name: "db"}
+
+	differences, err := env.Diff(&oldConfiguration, &newConfiguration, env.WithPrefix("APP"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pointerTo := func(s string) *string { return &s }
+	assert.Equal(t, []env.Difference{
+		{Key: "APP_TIMEOUT", Kind: env.DifferenceChanged, Old: pointerTo("1s"), New: pointerTo("1m0s")},
+		{Key: "APP_LIMIT", Kind: env.DifferenceRemoved, Old: pointerTo("5")},
+		{Key: "APP_HOSTS", Kind: env.DifferenceChanged, Old: pointerTo("a,b"), New: pointerTo("a,b,c")},
+		{Key: "APP_PASSWORD", Kind: env.DifferenceChanged, Old: pointerTo(env.RedactedValue), New: pointerTo(env.RedactedValue), Secret: true},
+		{Key: "APP_DATABASE_NAME", Kind: env.DifferenceAdded, New: pointerTo("db")},
+	}, differences)
+
+	var lines []string
+	for _, difference := range differences {
+		lines = append(lines, difference.String())
+	}
+	assert.Equal(t, []string{
+		"~ APP_TIMEOUT: 1s -> 1m0s",
+		"- APP_LIMIT=5",
+		"~ APP_HOSTS: a,b -> a,b,c",
+		"~ APP_PASSWORD: REDACTED -> REDACTED",
+		"+ APP_DATABASE_NAME=db",
+	}, lines)
+}
+
+func Test_Diff_Equal(t *testing.T) {
+	configuration := diffConfiguration{
+		Host:     "localhost",
+		Password: "secret",
+		Hosts:    []string{},
+	}
+	differences, err := env.Diff(configuration, diffConfiguration{Host: "localhost", Password: "secret"})
+	if assert.NoError(t, err) {
+		assert.Empty(t, differences)
+	}
+}
+
+func Test_Diff_DifferentTypes(t *testing.T) {
+	_, err := env.Diff(diffConfiguration{}, marshalConfiguration{})
+	assert.ErrorIs(t, err, yagcl.ErrInvalidConfiguraionPointer)
+}
@@ -0,0 +1,160 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFiles creates the given files relative to the directory, including
+// all parent directories.
+func writeFiles(t *testing.T, directory string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(directory, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_Discover_Parents(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":             "module example",
+		".env":               "A=root",
+		"service/.env":       "A=service",
+		"service/cmd/app.go": "package main",
+		"tools/cmd/tool.go":  "package main",
+	})
+
+	testCases := []struct {
+		name      string
+		directory string
+		expected  string
+	}{
+		{name: "same directory", directory: root, expected: filepath.Join(root, ".env")},
+		{name: "nearest parent", directory: filepath.Join(root, "service", "cmd"), expected: filepath.Join(root, "service", ".env")},
+		{name: "project root", directory: filepath.Join(root, "tools", "cmd"), expected: filepath.Join(root, ".env")},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c, report, err := env.ParseWithReport[dotenvConfiguration](env.WithDiscover(testCase.directory, ".env"))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			content, _ := os.ReadFile(testCase.expected)
+			assert.Equal(t, string(content)[2:], c.A)
+			if provenance, ok := report.Lookup("A"); assert.True(t, ok) {
+				assert.Equal(t, env.SourceKindFile, provenance.SourceKind)
+				assert.Equal(t, testCase.expected, provenance.Path)
+				assert.Equal(t, 1, provenance.Line)
+			}
+		})
+	}
+}
+
+func Test_Discover_StopsAtMarkers(t *testing.T) {
+	for _, marker := range []string{"go.mod", ".git/HEAD"} {
+		t.Run(marker, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, map[string]string{
+				".env":                 "A=outside",
+				"project/" + marker:    "",
+				"project/cmd/app/a.go": "package main",
+			})
+			directory := filepath.Join(root, "project", "cmd", "app")
+
+			c, err := env.Parse[dotenvConfiguration](env.WithDiscover(directory, ".env"))
+			if assert.NoError(t, err) {
+				assert.Empty(t, c.A)
+			}
+
+			_, err = env.Parse[dotenvConfiguration](env.WithDiscover(directory, ".env"), env.WithMust())
+			assert.ErrorIs(t, err, yagcl.ErrSourceNotFound)
+			assert.Contains(t, err.Error(), filepath.Join(root, "project"))
+		})
+	}
+}
+
+func Test_Discover_WorkingDirectory(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".git/HEAD":  "",
+		"config.env": "A=a",
+		"sub/a.go":   "package main",
+	})
+
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(root, "sub")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(workingDirectory)
+	})
+
+	var c dotenvConfiguration
+	err = yagcl.New[dotenvConfiguration]().
+		Add(env.Source().Discover("config.env").Must()).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a", c.A)
+	}
+}
+
+func Test_Discover_RepeatedOnParse(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":   "module example",
+		".env":     "A=root",
+		"sub/a.go": "package main",
+	})
+
+	source := env.Source().DiscoverFrom(filepath.Join(root, "sub"), ".env").Must()
+	var c dotenvConfiguration
+	if err := yagcl.New[dotenvConfiguration]().Add(source).Parse(&c); assert.NoError(t, err) {
+		assert.Equal(t, "root", c.A)
+	}
+
+	writeFiles(t, root, map[string]string{"sub/.env": "A=sub"})
+	c = dotenvConfiguration{}
+	if err := yagcl.New[dotenvConfiguration]().Add(source).Parse(&c); assert.NoError(t, err) {
+		assert.Equal(t, "sub", c.A)
+	}
+}
+
+func Test_Discover_LoadIntoEnv(t *testing.T) {
+	unsetEnv(t, "DISCOVERED")
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod": "module example",
+		".env":   "DISCOVERED=yes",
+	})
+
+	restore, err := env.LoadIntoEnv(env.WithDiscover(root, ".env"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "yes", os.Getenv("DISCOVERED"))
+		assert.NoError(t, restore())
+	}
+}
+
+func Test_Discover_MultipleSources(t *testing.T) {
+	stepOne := env.Source()
+	stepOne.Path("irrelevant.env")
+	stepOne.Discover(".env")
+	if source, ok := stepOne.(yagcl.Source); assert.True(t, ok) {
+		loaded, err := source.Parse(nil, nil)
+		assert.False(t, loaded)
+		assert.ErrorIs(t, err, env.ErrMultipleDataSourcesSpecified)
+	}
+}
@@ -0,0 +1,113 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type dotenvConfiguration struct {
+	A string `key:"a"`
+	B string `key:"b"`
+	C string `key:"c"`
+}
+
+func Test_Dotenv_Grammar(t *testing.T) {
+	t.Setenv("DOTENV_PROCESS", "process")
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected dotenvConfiguration
+	}{
+		{name: "empty", content: ""},
+		{name: "comments and blank lines", content: "# comment\n\n   \n\t# indented\nA=a", expected: dotenvConfiguration{A: "a"}},
+		{name: "line endings", content: "A=a\r\nB=b\rC=c\n", expected: dotenvConfiguration{A: "a", B: "b", C: "c"}},
+		{name: "byte order mark", content: "\ufeffA=a", expected: dotenvConfiguration{A: "a"}},
+		{name: "whitespace", content: "  A =  a b  \nB\t=\tb", expected: dotenvConfiguration{A: "a b", B: "b"}},
+		{name: "colon delimiter", content: "A: a", expected: dotenvConfiguration{A: "a"}},
+		{name: "export", content: "export A=a\nexport\tB=b", expected: dotenvConfiguration{A: "a", B: "b"}},
+		{name: "inline comments", content: "A=a # comment\nB=\"b\" # comment\nC='c'#comment", expected: dotenvConfiguration{A: "a", B: "b", C: "c"}},
+		{name: "hash without whitespace", content: "A=http://host/#anchor", expected: dotenvConfiguration{A: "http://host/#anchor"}},
+		{name: "single quotes", content: `A='a "b" $C \n # d'`, expected: dotenvConfiguration{A: `a "b" $C \n # d`}},
+		{name: "double quotes", content: `A="a 'b' \"c\" \\ \$d \t\x # e"`, expected: dotenvConfiguration{A: "a 'b' \"c\" \\ $d \t\\x # e"}},
+		{name: "multiline", content: "A=\"first\r\nsecond\nthird\"\nB=b", expected: dotenvConfiguration{A: "first\nsecond\nthird", B: "b"}},
+		{name: "escaped newline", content: `A="first\nsecond\r"`, expected: dotenvConfiguration{A: "first\nsecond\r"}},
+		{name: "quotes in unquoted value", content: `A=a"b'c`, expected: dotenvConfiguration{A: `a"b'c`}},
+		{name: "expansion", content: "A=a\nB=${A}-$A-$DOTENV_PROCESS-${UNKNOWN_DOTENV}\nC=\"$B\"", expected: dotenvConfiguration{A: "a", B: "a-a-process-", C: "a-a-process-"}},
+		{name: "escaped expansion", content: `A=\$A
+B="\${A}"
+C=$ 1`, expected: dotenvConfiguration{A: "$A", B: "${A}", C: "$ 1"}},
+		{name: "duplicates", content: "A=a\nA=b", expected: dotenvConfiguration{A: "b"}},
+		{name: "empty values", content: "A=\nB=''\nC=\"\"", expected: dotenvConfiguration{}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c, err := env.Parse[dotenvConfiguration](env.WithBytes([]byte(testCase.content)))
+			if assert.NoError(t, err) {
+				assert.Equal(t, testCase.expected, c)
+			}
+		})
+	}
+}
+
+func Test_Dotenv_SyntaxErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected env.SyntaxError
+	}{
+		{name: "invalid key", content: "A=a\n-B=b", expected: env.SyntaxError{Line: 2, Column: 1, Message: "unexpected character '-', expected a key"}},
+		{name: "missing delimiter", content: "A", expected: env.SyntaxError{Line: 1, Column: 2, Message: "missing '=' after key 'A'"}},
+		{name: "invalid key character", content: "  ÄB=b", expected: env.SyntaxError{Line: 1, Column: 3, Message: "unexpected character 'Ä', expected a key"}},
+		{name: "whitespace in key", content: "A B=b", expected: env.SyntaxError{Line: 1, Column: 3, Message: "unexpected character 'B' in key 'A'"}},
+		{name: "unterminated double quotes", content: "A=a\nB=\"b\nc", expected: env.SyntaxError{Line: 2, Column: 3, Message: "unterminated double quoted value"}},
+		{name: "unterminated single quotes", content: "A='a\nb'", expected: env.SyntaxError{Line: 1, Column: 3, Message: "unterminated single quoted value"}},
+		{name: "content after quotes", content: "A=\"a\nb\" c", expected: env.SyntaxError{Line: 2, Column: 4, Message: "unexpected character 'c' after value of key 'A'"}},
+		{name: "unterminated reference", content: "A=${B", expected: env.SyntaxError{Line: 1, Column: 3, Message: "unterminated variable reference"}},
+		{name: "invalid reference", content: "A=ä${1B}", expected: env.SyntaxError{Line: 1, Column: 4, Message: "invalid variable name '1B'"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := env.Parse[dotenvConfiguration](env.WithBytes([]byte(testCase.content)))
+			var syntaxError *env.SyntaxError
+			if assert.True(t, errors.As(err, &syntaxError), "unexpected error: %v", err) {
+				assert.Equal(t, testCase.expected, *syntaxError)
+			}
+		})
+	}
+}
+
+func Test_Dotenv_SyntaxError_Path(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.env")
+	if !assert.NoError(t, os.WriteFile(path, []byte("A=a\nB"), 0o600)) {
+		return
+	}
+
+	_, err := env.Parse[dotenvConfiguration](env.WithPath(path))
+	assert.EqualError(t, err, path+":2:2: missing '=' after key 'B'")
+
+	_, err = env.Parse[dotenvConfiguration](env.WithBytes([]byte("A=a\nB")))
+	assert.EqualError(t, err, "line 2, column 2: missing '=' after key 'B'")
+}
+
+func Test_Dotenv_MarshalRoundTrip(t *testing.T) {
+	expected := dotenvConfiguration{
+		A: "a \"quoted\" $value with \\ and # and ä",
+		B: "multi\nline\r\nvalue",
+		C: "'single' \\$ \\n",
+	}
+	data, err := env.Marshal(expected)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c, err := env.Parse[dotenvConfiguration](env.WithBytes(data))
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, c)
+	}
+}
@@ -0,0 +1,73 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+const duplicatesContent = "A=1\nB=2\n\nA=3\nA=4\n"
+
+func Test_Duplicates_Ignored(t *testing.T) {
+	c, err := env.Parse[dotenvConfiguration](env.WithBytes([]byte(duplicatesContent)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "4", c.A)
+	}
+}
+
+func Test_Duplicates_Strict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if !assert.NoError(t, os.WriteFile(path, []byte(duplicatesContent), 0o600)) {
+		return
+	}
+
+	_, err := env.Parse[dotenvConfiguration](env.WithPath(path), env.WithStrictDuplicates())
+	assert.ErrorIs(t, err, env.ErrDuplicateKeys)
+	assert.EqualError(t, err, "duplicate keys: "+
+		path+": key 'A' in line 4 has already been defined in line 1; "+
+		path+": key 'A' in line 5 has already been defined in line 4")
+
+	_, err = env.Parse[dotenvConfiguration](env.WithBytes([]byte("A=1\nB=2")), env.WithStrictDuplicates())
+	assert.NoError(t, err)
+}
+
+func Test_Duplicates_Callback(t *testing.T) {
+	var duplicates []env.Duplicate
+	c, err := env.Parse[dotenvConfiguration](
+		env.WithBytes([]byte(duplicatesContent)),
+		env.WithOnDuplicate(func(duplicate env.Duplicate) {
+			duplicates = append(duplicates, duplicate)
+		}))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "4", c.A)
+	assert.Equal(t, []env.Duplicate{
+		{Key: "A", PreviousLine: 1, Line: 4},
+		{Key: "A", PreviousLine: 4, Line: 5},
+	}, duplicates)
+	assert.Equal(t, "key 'A' in line 4 has already been defined in line 1", duplicates[0].String())
+}
+
+func Test_Duplicates_CascadeOverridesAllowed(t *testing.T) {
+	var duplicates []env.Duplicate
+	onDuplicate := func(duplicate env.Duplicate) {
+		duplicates = append(duplicates, duplicate)
+	}
+
+	var c dotenvConfiguration
+	err := yagcl.New[dotenvConfiguration]().
+		Add(env.Source().String("A=1\nB=2").StrictDuplicates().OnDuplicate(onDuplicate)).
+		Add(env.Source().String("A=3").StrictDuplicates().OnDuplicate(onDuplicate)).
+		AllowOverride().
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, dotenvConfiguration{A: "3", B: "2"}, c)
+		assert.Empty(t, duplicates)
+	}
+}
@@ -0,0 +1,91 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type environConfiguration struct {
+	Host     string        `key:"host"`
+	Timeout  time.Duration `key:"timeout"`
+	Limit    *int          `key:"limit"`
+	Hosts    []string      `key:"hosts"`
+	Password string        `key:"password" secret:"true"`
+	Database struct {
+		Host string `key:"host"`
+		Name string `key:"name"`
+	} `key:"database"`
+}
+
+func newEnvironConfiguration() environConfiguration {
+	configuration := environConfiguration{
+		Host:     "local host",
+		Timeout:  time.Second,
+		Hosts:    []string{"a", "b"},
+		Password: "secret",
+	}
+	configuration.Database.Host = "db"
+	configuration.Database.Name = "app"
+	return configuration
+}
+
+func Test_Environ(t *testing.T) {
+	entries, err := env.Environ(newEnvironConfiguration(), env.WithPrefix("APP"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			"APP_HOST=local host",
+			"APP_TIMEOUT=1s",
+			"APP_HOSTS=a,b",
+			"APP_PASSWORD=secret",
+			"APP_DATABASE_HOST=db",
+			"APP_DATABASE_NAME=app",
+		}, entries)
+	}
+}
+
+func Test_Environ_Merged(t *testing.T) {
+	base := []string{"PATH=/bin", "APP_HOST=example.com", "=C:=C:\\", "APP_OTHER=value"}
+
+	entries, err := env.Environ(newEnvironConfiguration(),
+		env.WithPrefix("APP"),
+		env.WithMergedEnviron(base, env.PreferConfiguration),
+		env.WithFieldAllowlist("Host"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"PATH=/bin", "=C:=C:\\", "APP_OTHER=value", "APP_HOST=local host"}, entries)
+	}
+
+	entries, err = env.Environ(newEnvironConfiguration(),
+		env.WithPrefix("APP"),
+		env.WithMergedEnviron(base, env.PreferEnviron),
+		env.WithFieldAllowlist("Host"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, base, entries)
+	}
+}
+
+func Test_Environ_Filters(t *testing.T) {
+	entries, err := env.Environ(newEnvironConfiguration(),
+		env.WithPrefix("APP"),
+		env.WithMergedEnviron([]string{"PATH=/bin", "APP_OTHER=value"}, env.PreferConfiguration),
+		env.WithPrefixFilter("APP_DATABASE_", "PATH"),
+		env.WithRedactedSecrets())
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"PATH=/bin", "APP_DATABASE_HOST=db", "APP_DATABASE_NAME=app"}, entries)
+	}
+
+	entries, err = env.Environ(newEnvironConfiguration(),
+		env.WithFieldAllowlist("Database", "Password"),
+		env.WithRedactedSecrets())
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"PASSWORD=REDACTED", "DATABASE_HOST=db", "DATABASE_NAME=app"}, entries)
+	}
+}
+
+func Test_Environ_InvalidConfiguration(t *testing.T) {
+	_, err := env.Environ("string")
+	assert.ErrorIs(t, err, yagcl.ErrInvalidConfiguraionPointer)
+}
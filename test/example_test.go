@@ -0,0 +1,49 @@
+package env
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteExample(t *testing.T) {
+	type configuration struct {
+		Host     string        `key:"host" desc:"Host to listen on"`
+		Timeout  time.Duration `key:"timeout" doc:"Request timeout" default:"30s"`
+		Hosts    []string      `key:"hosts"`
+		Password string        `key:"password" secret:"true" required:"true"`
+		Database *struct {
+			URL string `env:"URL,required"`
+		} `key:"database"`
+	}
+
+	c := configuration{
+		Host:     "localhost",
+		Hosts:    []string{"a,b", "c"},
+		Password: "hunter2",
+	}
+	var buffer bytes.Buffer
+	err := env.WriteExample(&buffer, &c, env.WithPrefix("APP"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, `# Host to listen on
+# Type: string
+APP_HOST=localhost
+
+# Request timeout
+# Type: time.Duration
+APP_TIMEOUT=30s
+
+# Type: []string
+APP_HOSTS="a\\,b,c"
+
+# Type: string (required)
+APP_PASSWORD=
+
+# Type: string (required)
+APP_DATABASE_URL=
+`, buffer.String())
+	}
+}
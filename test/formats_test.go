@@ -0,0 +1,168 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type formatsConfiguration struct {
+	Name     string `key:"name"`
+	Database struct {
+		Host string `key:"host"`
+		Port int    `key:"port"`
+	} `key:"database"`
+	LogLevel string `key:"log_level"`
+}
+
+func Test_Formats_Properties(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected dotenvConfiguration
+	}{
+		{name: "empty", content: ""},
+		{name: "comments and blank lines", content: "# comment\n! comment\n\n  \t\n  # indented\na=a", expected: dotenvConfiguration{A: "a"}},
+		{name: "delimiters", content: "a=a\nb:b\nc c", expected: dotenvConfiguration{A: "a", B: "b", C: "c"}},
+		{name: "whitespace around delimiters", content: "a \t= \ta b \nb  :b\nc   c", expected: dotenvConfiguration{A: "a b ", B: "b", C: "c"}},
+		{name: "key without value", content: "a\nb=\nc:", expected: dotenvConfiguration{}},
+		{name: "delimiters in value", content: "a=b=c:d", expected: dotenvConfiguration{A: "b=c:d"}},
+		{name: "escapes", content: `a=\t\n\r\f\\\#\x`, expected: dotenvConfiguration{A: "\t\n\r\f\\#x"}},
+		{name: "escaped delimiters in key", content: `a\=b=c`},
+		{name: "unicode escapes", content: `a=\u00e4\u20AC`, expected: dotenvConfiguration{A: "ä€"}},
+		{name: "continuations", content: "a=first \\\n    second\\\r\n\tthird\nb=b", expected: dotenvConfiguration{A: "first secondthird", B: "b"}},
+		{name: "comment characters in continuation", content: "a=a\\\n  # b", expected: dotenvConfiguration{A: "a# b"}},
+		{name: "trailing backslash", content: `a=a\`, expected: dotenvConfiguration{A: "a"}},
+		{name: "duplicates", content: "a=a\na=b", expected: dotenvConfiguration{A: "b"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c, err := env.Parse[dotenvConfiguration](
+				env.WithBytes([]byte(testCase.content)),
+				env.WithFormat(env.FormatProperties))
+			if assert.NoError(t, err) {
+				assert.Equal(t, testCase.expected, c)
+			}
+		})
+	}
+}
+
+func Test_Formats_Properties_KeyMapping(t *testing.T) {
+	content := "name=app\ndatabase.host=localhost\ndatabase.port=5432\nlog-level=debug"
+	c, err := env.Parse[formatsConfiguration](
+		env.WithBytes([]byte(content)),
+		env.WithFormat(env.FormatProperties))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "app", c.Name)
+		assert.Equal(t, "localhost", c.Database.Host)
+		assert.Equal(t, 5432, c.Database.Port)
+		assert.Equal(t, "debug", c.LogLevel)
+	}
+}
+
+func Test_Formats_Properties_MalformedUnicode(t *testing.T) {
+	for _, content := range []string{"a=\\u12", "a=b\\u12x4"} {
+		_, err := env.Parse[dotenvConfiguration](
+			env.WithBytes([]byte(content)),
+			env.WithFormat(env.FormatProperties))
+		var syntaxError *env.SyntaxError
+		if assert.True(t, errors.As(err, &syntaxError), "unexpected error: %v", err) {
+			assert.Equal(t, "malformed \\uXXXX encoding", syntaxError.Message)
+			assert.Equal(t, strings.IndexByte(content, '\\')+1, syntaxError.Column)
+		}
+	}
+}
+
+func Test_Formats_INI(t *testing.T) {
+	content := `; comment
+# comment
+name = app
+log-level: debug
+
+[ database ]
+host = "localhost"
+port=5432
+`
+	c, err := env.Parse[formatsConfiguration](
+		env.WithBytes([]byte(content)),
+		env.WithFormat(env.FormatINI))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "app", c.Name)
+		assert.Equal(t, "localhost", c.Database.Host)
+		assert.Equal(t, 5432, c.Database.Port)
+		assert.Equal(t, "debug", c.LogLevel)
+	}
+}
+
+func Test_Formats_INI_SyntaxErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected env.SyntaxError
+	}{
+		{name: "unterminated section header", content: "a=a\n  [section", expected: env.SyntaxError{Line: 2, Column: 3, Message: "unterminated section header"}},
+		{name: "missing section name", content: "[ ]", expected: env.SyntaxError{Line: 1, Column: 1, Message: "missing section name"}},
+		{name: "missing delimiter", content: "a=a\n\ta", expected: env.SyntaxError{Line: 2, Column: 2, Message: "missing '=' after key 'a'"}},
+		{name: "missing key", content: "=a", expected: env.SyntaxError{Line: 1, Column: 1, Message: "missing key"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := env.Parse[dotenvConfiguration](
+				env.WithBytes([]byte(testCase.content)),
+				env.WithFormat(env.FormatINI))
+			var syntaxError *env.SyntaxError
+			if assert.True(t, errors.As(err, &syntaxError), "unexpected error: %v", err) {
+				assert.Equal(t, testCase.expected, *syntaxError)
+			}
+		})
+	}
+}
+
+func Test_Formats_CustomKeyMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.ini")
+	if !assert.NoError(t, os.WriteFile(path, []byte("[app]\nname=app"), 0o600)) {
+		return
+	}
+
+	c, err := env.Parse[formatsConfiguration](
+		env.WithPath(path),
+		env.WithFormat(env.FormatINI),
+		env.WithKeyMapping(func(key string) string {
+			return strings.ToUpper(strings.TrimPrefix(key, "app."))
+		}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "app", c.Name)
+	}
+
+	// Key mappings also apply to FormatEnv.
+	c, err = env.Parse[formatsConfiguration](
+		env.WithBytes([]byte("APP_NAME=app")),
+		env.WithKeyMapping(func(key string) string {
+			return strings.ToUpper(strings.TrimPrefix(key, "APP_"))
+		}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "app", c.Name)
+	}
+}
+
+func Test_Formats_Duplicates(t *testing.T) {
+	_, err := env.Parse[formatsConfiguration](
+		env.WithBytes([]byte("database.host=a\nDATABASE_HOST=b")),
+		env.WithFormat(env.FormatProperties),
+		env.WithStrictDuplicates())
+	assert.ErrorIs(t, err, env.ErrDuplicateKeys)
+	assert.EqualError(t, err, "duplicate keys: key 'DATABASE_HOST' in line 2 has already been defined in line 1")
+}
+
+func Test_Formats_Unsupported(t *testing.T) {
+	_, err := env.Parse[dotenvConfiguration](
+		env.WithBytes([]byte("a=a")),
+		env.WithFormat("yaml"))
+	assert.ErrorIs(t, err, env.ErrUnsupportedFormat)
+	assert.EqualError(t, err, "format 'yaml': unsupported format")
+}
@@ -0,0 +1,132 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Fragments_LexicalOrder(t *testing.T) {
+	directory := t.TempDir()
+	writeFiles(t, directory, map[string]string{
+		"env.d/20-override.env": "\nA=override\nB=b",
+		"env.d/10-base.env":     "A=base\nC=c",
+		"env.d/README":          "A=ignored",
+	})
+
+	c, report, err := env.ParseWithReport[dotenvConfiguration](
+		env.WithPaths(filepath.Join(directory, "env.d", "*.env")),
+		env.WithStrictDuplicates())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, dotenvConfiguration{A: "override", B: "b", C: "c"}, c)
+	for key, expected := range map[string]struct {
+		file string
+		line int
+	}{
+		"A": {file: "20-override.env", line: 2},
+		"B": {file: "20-override.env", line: 3},
+		"C": {file: "10-base.env", line: 2},
+	} {
+		if provenance, ok := report.Lookup(key); assert.True(t, ok, key) {
+			assert.Equal(t, env.SourceKindFile, provenance.SourceKind, key)
+			assert.Equal(t, filepath.Join(directory, "env.d", expected.file), provenance.Path, key)
+			assert.Equal(t, expected.line, provenance.Line, key)
+		}
+	}
+}
+
+func Test_Fragments_GivenOrder(t *testing.T) {
+	directory := t.TempDir()
+	writeFiles(t, directory, map[string]string{
+		"b.env": "A=b\nB=b",
+		"a.env": "A=a",
+	})
+
+	var c dotenvConfiguration
+	err := yagcl.New[dotenvConfiguration]().
+		Add(env.Source().Paths(filepath.Join(directory, "b.env"), filepath.Join(directory, "a.env")).Must()).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, dotenvConfiguration{A: "a", B: "b"}, c)
+	}
+}
+
+func Test_Fragments_Missing(t *testing.T) {
+	directory := t.TempDir()
+	writeFiles(t, directory, map[string]string{"a.env": "A=a"})
+	existing := filepath.Join(directory, "a.env")
+	missing := filepath.Join(directory, "missing.env")
+	noMatches := filepath.Join(directory, "env.d", "*.env")
+
+	c, err := env.Parse[dotenvConfiguration](env.WithPaths(existing, missing, noMatches))
+	if assert.NoError(t, err) {
+		assert.Equal(t, dotenvConfiguration{A: "a"}, c)
+	}
+
+	c, err = env.Parse[dotenvConfiguration](env.WithPaths(missing, noMatches))
+	if assert.NoError(t, err) {
+		assert.Equal(t, dotenvConfiguration{}, c)
+	}
+
+	_, err = env.Parse[dotenvConfiguration](env.WithPaths(existing, missing), env.WithMust())
+	assert.ErrorIs(t, err, yagcl.ErrSourceNotFound)
+	assert.Contains(t, err.Error(), "fragment '"+missing+"'")
+
+	_, err = env.Parse[dotenvConfiguration](env.WithPaths(existing, noMatches), env.WithMust())
+	assert.ErrorIs(t, err, yagcl.ErrSourceNotFound)
+	assert.Contains(t, err.Error(), "fragment '"+noMatches+"'")
+}
+
+func Test_Fragments_Errors(t *testing.T) {
+	directory := t.TempDir()
+	writeFiles(t, directory, map[string]string{
+		"10-valid.env":      "A=a\nB=b",
+		"20-invalid.env":    "A=a\nB",
+		"30-duplicates.env": "C=c\nC=d",
+	})
+	valid := filepath.Join(directory, "10-valid.env")
+	invalid := filepath.Join(directory, "20-invalid.env")
+	duplicates := filepath.Join(directory, "30-duplicates.env")
+
+	_, err := env.Parse[dotenvConfiguration](env.WithPaths(filepath.Join(directory, "*.env")))
+	var syntaxError *env.SyntaxError
+	if assert.True(t, errors.As(err, &syntaxError), "unexpected error: %v", err) {
+		assert.Equal(t, invalid, syntaxError.Path)
+	}
+
+	// Keys defined in multiple fragments are overrides, not duplicates.
+	_, err = env.Parse[dotenvConfiguration](env.WithPaths(valid, duplicates), env.WithStrictDuplicates())
+	assert.ErrorIs(t, err, env.ErrDuplicateKeys)
+	assert.EqualError(t, err, "duplicate keys: "+duplicates+": key 'C' in line 2 has already been defined in line 1")
+
+	_, err = env.Parse[dotenvConfiguration](env.WithPaths(filepath.Join(directory, "[")))
+	assert.ErrorIs(t, err, filepath.ErrBadPattern)
+}
+
+func Test_Fragments_LoadIntoEnv(t *testing.T) {
+	unsetEnv(t, "FRAGMENT_A", "FRAGMENT_B")
+	directory := t.TempDir()
+	writeFiles(t, directory, map[string]string{
+		"1.env": "FRAGMENT_A=1\nFRAGMENT_B=1",
+		"2.env": "FRAGMENT_B=2",
+	})
+
+	result := &env.LoadResult{}
+	var c dotenvConfiguration
+	err := yagcl.New[dotenvConfiguration]().
+		Add(env.Source().Paths(filepath.Join(directory, "*.env")).LoadIntoEnv().LoadResult(result)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assertLoadResult(t, env.LoadResult{Set: []string{"FRAGMENT_A", "FRAGMENT_B"}}, *result)
+		assert.Equal(t, "2", os.Getenv("FRAGMENT_B"))
+		assert.NoError(t, result.Restore())
+	}
+}
@@ -0,0 +1,206 @@
+package env
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type loadConfiguration struct {
+	Host string `key:"host"`
+	Port int    `key:"port"`
+}
+
+const loadContent = "APP_HOST=localhost\nAPP_PORT=8080\nOTHER=value\n"
+
+// unsetEnv removes the given keys from the environment for the duration of
+// the test, restoring them afterwards.
+func unsetEnv(t *testing.T, keys ...string) {
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+// assertLoadResult compares the keys of both results, ignoring the values
+// recorded for restoring.
+func assertLoadResult(t *testing.T, expected, actual env.LoadResult) {
+	assert.Equal(t, expected.Set, actual.Set, "Set")
+	assert.Equal(t, expected.Skipped, actual.Skipped, "Skipped")
+	assert.Equal(t, expected.Conflicted, actual.Conflicted, "Conflicted")
+}
+
+func Test_LoadIntoEnv_Overwrite(t *testing.T) {
+	unsetEnv(t, "APP_PORT", "OTHER")
+	t.Setenv("APP_HOST", "example.com")
+
+	var result env.LoadResult
+	var c loadConfiguration
+	err := yagcl.New[loadConfiguration]().
+		Add(env.Source().String(loadContent).Prefix("APP").LoadIntoEnv().LoadResult(&result)).
+		Parse(&c)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "localhost", os.Getenv("APP_HOST"))
+	assert.Equal(t, "value", os.Getenv("OTHER"))
+	assertLoadResult(t, env.LoadResult{
+		Set:        []string{"APP_HOST", "APP_PORT", "OTHER"},
+		Conflicted: []string{"APP_HOST"},
+	}, result)
+}
+
+func Test_LoadIntoEnv_KeepExisting(t *testing.T) {
+	unsetEnv(t, "APP_PORT", "OTHER")
+	t.Setenv("APP_HOST", "example.com")
+
+	var result env.LoadResult
+	var c loadConfiguration
+	err := yagcl.New[loadConfiguration]().
+		Add(env.Source().String(loadContent).Prefix("APP").LoadIntoEnvPolicy(env.LoadKeepExisting).LoadResult(&result)).
+		Parse(&c)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "example.com", os.Getenv("APP_HOST"))
+	assert.Equal(t, "8080", os.Getenv("APP_PORT"))
+	assertLoadResult(t, env.LoadResult{
+		Set:        []string{"APP_PORT", "OTHER"},
+		Skipped:    []string{"APP_HOST"},
+		Conflicted: []string{"APP_HOST"},
+	}, result)
+}
+
+func Test_LoadIntoEnv_FailOnConflict(t *testing.T) {
+	unsetEnv(t, "APP_PORT", "OTHER")
+	t.Setenv("APP_HOST", "example.com")
+
+	var result env.LoadResult
+	var c loadConfiguration
+	err := yagcl.New[loadConfiguration]().
+		Add(env.Source().String(loadContent).Prefix("APP").LoadIntoEnvPolicy(env.LoadFailOnConflict).LoadResult(&result)).
+		Parse(&c)
+	assert.ErrorIs(t, err, env.ErrLoadConflict)
+	assert.Contains(t, err.Error(), "APP_HOST")
+	assertLoadResult(t, env.LoadResult{Conflicted: []string{"APP_HOST"}}, result)
+
+	_, present := os.LookupEnv("APP_PORT")
+	assert.False(t, present)
+}
+
+func Test_LoadIntoEnv_FailOnConflict_EqualValue(t *testing.T) {
+	unsetEnv(t, "APP_PORT", "OTHER")
+	t.Setenv("APP_HOST", "localhost")
+
+	var result env.LoadResult
+	var c loadConfiguration
+	err := yagcl.New[loadConfiguration]().
+		Add(env.Source().String(loadContent).LoadIntoEnvPolicy(env.LoadFailOnConflict).LoadResult(&result)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assertLoadResult(t, env.LoadResult{Set: []string{"APP_HOST", "APP_PORT", "OTHER"}}, result)
+	}
+}
+
+func Test_LoadIntoEnv_PrefixedOnly(t *testing.T) {
+	unsetEnv(t, "APP_HOST", "APP_PORT", "OTHER")
+
+	var result env.LoadResult
+	var c loadConfiguration
+	err := yagcl.New[loadConfiguration]().
+		Add(env.Source().String(loadContent).Prefix("APP").LoadIntoEnv().LoadPrefixedOnly().LoadResult(&result)).
+		Parse(&c)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"APP_HOST", "APP_PORT"}, result.Set)
+	_, present := os.LookupEnv("OTHER")
+	assert.False(t, present)
+}
+
+func Test_LoadIntoEnv_OnlyAfterSuccessfulParse(t *testing.T) {
+	unsetEnv(t, "APP_HOST", "APP_PORT", "OTHER")
+
+	var result env.LoadResult
+	var c loadConfiguration
+	err := yagcl.New[loadConfiguration]().
+		Add(env.Source().String("APP_HOST=localhost\nAPP_PORT=invalid").Prefix("APP").LoadIntoEnv().LoadResult(&result)).
+		Parse(&c)
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+	assertLoadResult(t, env.LoadResult{}, result)
+
+	_, present := os.LookupEnv("APP_HOST")
+	assert.False(t, present)
+}
+
+func Test_LoadResult_Restore(t *testing.T) {
+	unsetEnv(t, "APP_PORT", "OTHER")
+	t.Setenv("APP_HOST", "example.com")
+
+	var result env.LoadResult
+	var c loadConfiguration
+	err := yagcl.New[loadConfiguration]().
+		Add(env.Source().String(loadContent).Prefix("APP").LoadIntoEnv().LoadResult(&result)).
+		Parse(&c)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "localhost", os.Getenv("APP_HOST"))
+
+	assert.NoError(t, result.Restore())
+	assert.Equal(t, "example.com", os.Getenv("APP_HOST"))
+	_, present := os.LookupEnv("APP_PORT")
+	assert.False(t, present)
+
+	// Restoring again mustn't touch variables modified in the meantime.
+	t.Setenv("APP_PORT", "1")
+	assert.NoError(t, result.Restore())
+	assert.Equal(t, "1", os.Getenv("APP_PORT"))
+}
+
+func Test_StandaloneLoadIntoEnv(t *testing.T) {
+	unsetEnv(t, "APP_PORT", "OTHER")
+	t.Setenv("APP_HOST", "example.com")
+
+	restore, err := env.LoadIntoEnv(
+		env.WithBytes([]byte(loadContent)),
+		env.WithPrefix("APP"),
+		env.WithLoadPolicy(env.LoadKeepExisting),
+		env.WithLoadPrefixedOnly(),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "example.com", os.Getenv("APP_HOST"))
+	assert.Equal(t, "8080", os.Getenv("APP_PORT"))
+	_, present := os.LookupEnv("OTHER")
+	assert.False(t, present)
+
+	assert.NoError(t, restore())
+	_, present = os.LookupEnv("APP_PORT")
+	assert.False(t, present)
+}
+
+func Test_StandaloneLoadIntoEnv_Errors(t *testing.T) {
+	restore, err := env.LoadIntoEnv(env.WithPath("./doesntexist.env"))
+	assert.NoError(t, err)
+	assert.NoError(t, restore())
+
+	_, err = env.LoadIntoEnv(env.WithPath("./doesntexist.env"), env.WithMust())
+	assert.ErrorIs(t, err, yagcl.ErrSourceNotFound)
+
+	_, err = env.LoadIntoEnv()
+	assert.ErrorIs(t, err, env.ErrNoDataSourceSpecified)
+
+	t.Setenv("APP_HOST", "example.com")
+	restore, err = env.LoadIntoEnv(env.WithBytes([]byte(loadContent)), env.WithLoadPolicy(env.LoadFailOnConflict))
+	assert.ErrorIs(t, err, env.ErrLoadConflict)
+	assert.NoError(t, restore())
+	assert.Equal(t, "example.com", os.Getenv("APP_HOST"))
+}
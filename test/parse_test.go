@@ -0,0 +1,89 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StandaloneParse(t *testing.T) {
+	type configuration struct {
+		Host    string        `key:"host"`
+		Timeout time.Duration `key:"timeout" default:"30s"`
+		Ignored string        `key:"ignored" ignore:"true"`
+	}
+
+	t.Setenv("APP_HOST", "localhost")
+	t.Setenv("APP_IGNORED", "value")
+	c, err := env.Parse[configuration](env.WithPrefix("APP"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, configuration{Host: "localhost", Timeout: 30 * time.Second}, c)
+	}
+}
+
+func Test_StandaloneParse_Bytes(t *testing.T) {
+	type configuration struct {
+		Host string `key:"host"`
+	}
+
+	c, err := env.Parse[configuration](env.WithBytes([]byte("HOST=localhost")))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "localhost", c.Host)
+	}
+}
+
+func Test_StandaloneParse_Path(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a"`
+	}
+
+	c, err := env.Parse[configuration](env.WithPath("./test.env"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "content a", c.FieldA)
+	}
+
+	_, err = env.Parse[configuration](env.WithPath("./doesntexist.env"))
+	assert.NoError(t, err)
+	_, err = env.Parse[configuration](env.WithPath("./doesntexist.env"), env.WithMust())
+	assert.ErrorIs(t, err, yagcl.ErrSourceNotFound)
+}
+
+func Test_StandaloneParse_KeyTags(t *testing.T) {
+	type configuration struct {
+		Inferred string
+		Custom   string `custom:"custom_key"`
+	}
+
+	t.Setenv("INFERRED", "a")
+	t.Setenv("CUSTOM_KEY", "b")
+	c, err := env.Parse[configuration](env.WithInferFieldKeys(), env.WithAdditionalKeyTags("custom"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a", c.Inferred)
+		assert.Equal(t, "b", c.Custom)
+	}
+
+	_, err = env.Parse[configuration]()
+	assert.ErrorIs(t, err, yagcl.ErrExportedFieldMissingKey)
+}
+
+func Test_StandaloneParse_InvalidType(t *testing.T) {
+	_, err := env.Parse[int]()
+	assert.ErrorIs(t, err, yagcl.ErrInvalidConfiguraionPointer)
+}
+
+func Test_StandaloneMustParse(t *testing.T) {
+	type configuration struct {
+		Port int `key:"port"`
+	}
+
+	t.Setenv("PORT", "8080")
+	assert.Equal(t, 8080, env.MustParse[configuration]().Port)
+
+	t.Setenv("PORT", "not a port")
+	assert.Panics(t, func() {
+		env.MustParse[configuration]()
+	})
+}
@@ -0,0 +1,208 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type provenanceConfiguration struct {
+	Host     string        `key:"host"`
+	Timeout  time.Duration `key:"timeout" default:"30s"`
+	Port     int           `key:"port"`
+	Password string        `key:"password" secret:"true"`
+	Database struct {
+		Name string `key:"name"`
+	} `key:"database"`
+}
+
+func Test_ParseWithReport_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# comment\nAPP_HOST=localhost\n\nAPP_PASSWORD=\"multi\nline\"\nexport APP_DATABASE_NAME=db\nAPP_HOST=example.com\n"
+	if !assert.NoError(t, os.WriteFile(path, []byte(content), 0o600)) {
+		return
+	}
+
+	c, report, err := env.ParseWithReport[provenanceConfiguration](env.WithPath(path), env.WithPrefix("APP"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "example.com", c.Host)
+
+	pointerTo := func(s string) *string { return &s }
+	assert.Equal(t, []env.Provenance{
+		{Field: "Host", Key: "APP_HOST", Found: true, SourceKind: env.SourceKindFile, Path: path, Line: 7, Value: pointerTo("example.com")},
+		{Field: "Timeout", Key: "APP_TIMEOUT", Default: true, SourceKind: env.SourceKindFile, Path: path, Value: pointerTo("30s")},
+		{Field: "Port", Key: "APP_PORT", SourceKind: env.SourceKindFile, Path: path, Value: pointerTo("0")},
+		{Field: "Password", Key: "APP_PASSWORD", Found: true, SourceKind: env.SourceKindFile, Path: path, Line: 4, Secret: true, Value: pointerTo("multi\nline")},
+		{Field: "Database.Name", Key: "APP_DATABASE_NAME", Found: true, SourceKind: env.SourceKindFile, Path: path, Line: 6, Value: pointerTo("db")},
+	}, report.Fields)
+
+	provenance, ok := report.Lookup("APP_DATABASE_NAME")
+	if assert.True(t, ok) {
+		assert.Equal(t, "Database.Name", provenance.Field)
+	}
+	_, ok = report.Lookup("APP_UNKNOWN")
+	assert.False(t, ok)
+}
+
+func Test_ParseWithReport_Environment(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	_, report, err := env.ParseWithReport[provenanceConfiguration]()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	provenance, ok := report.Lookup("HOST")
+	if assert.True(t, ok) {
+		assert.True(t, provenance.Found)
+		assert.Equal(t, env.SourceKindEnvironment, provenance.SourceKind)
+		assert.Zero(t, provenance.Line)
+	}
+}
+
+func Test_ParseWithReport_Error(t *testing.T) {
+	_, report, err := env.ParseWithReport[provenanceConfiguration](env.WithBytes([]byte("HOST=localhost\nPORT=invalid")))
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+	if assert.NotNil(t, report) {
+		provenance, ok := report.Lookup("HOST")
+		if assert.True(t, ok) {
+			assert.Equal(t, env.SourceKindBytes, provenance.SourceKind)
+			assert.Equal(t, 1, provenance.Line)
+		}
+	}
+}
+
+func Test_Report_Cascade(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	report := &env.Report{}
+	var c provenanceConfiguration
+	err := yagcl.New[provenanceConfiguration]().
+		Add(env.Source().String("HOST=localhost\nPORT=80").Report(report)).
+		Add(env.Source().Env().Report(report)).
+		AllowOverride().
+		Parse(&c)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	host, _ := report.Lookup("HOST")
+	assert.Equal(t, env.SourceKindBytes, host.SourceKind)
+	assert.Equal(t, 1, host.Line)
+	assert.Equal(t, "localhost", *host.Value)
+
+	port, _ := report.Lookup("PORT")
+	assert.Equal(t, env.SourceKindEnvironment, port.SourceKind)
+	assert.Equal(t, "8080", *port.Value)
+}
+
+func Test_Report_ModifiedFields(t *testing.T) {
+	report := &env.Report{}
+	var c provenanceConfiguration
+	err := yagcl.New[provenanceConfiguration]().Add(env.Source().String("HOST=localhost\nPORT=80").Report(report)).Parse(&c)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Reports may be modified by the caller between parses, which mustn't
+	// cause entries to be lost or duplicated.
+	report.Fields[0], report.Fields[1] = report.Fields[1], report.Fields[0]
+	report.Fields = report.Fields[:len(report.Fields)-1]
+	err = yagcl.New[provenanceConfiguration]().Add(env.Source().String("HOST=remote\nPORT=81").Report(report)).Parse(&c)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	keys := make(map[string]bool)
+	for _, provenance := range report.Fields {
+		assert.False(t, keys[provenance.Key], "duplicate key %s", provenance.Key)
+		keys[provenance.Key] = true
+	}
+	host, _ := report.Lookup("HOST")
+	assert.Equal(t, "remote", *host.Value)
+	port, _ := report.Lookup("PORT")
+	assert.Equal(t, "81", *port.Value)
+}
+
+func Test_Report_String(t *testing.T) {
+	t.Setenv("PROVENANCE_HOST", "local host")
+	t.Setenv("PROVENANCE_PASSWORD", "hunter2")
+	_, report, err := env.ParseWithReport[provenanceConfiguration](env.WithPrefix("PROVENANCE"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, ""+
+		"FIELD          KEY                       VALUE         ORIGIN\n"+
+		"Host           PROVENANCE_HOST           \"local host\"  environment\n"+
+		"Timeout        PROVENANCE_TIMEOUT        30s           default tag\n"+
+		"Port           PROVENANCE_PORT           0             not found\n"+
+		"Password       PROVENANCE_PASSWORD       REDACTED      environment\n"+
+		"Database.Name  PROVENANCE_DATABASE_NAME                not found\n",
+		report.String())
+}
+
+// unmarshalOnlyText implements encoding.TextUnmarshaler, but not
+// encoding.TextMarshaler, so it can't be formatted the way Marshal does.
+type unmarshalOnlyText struct {
+	text string
+}
+
+func (u *unmarshalOnlyText) UnmarshalText(text []byte) error {
+	u.text = string(text)
+	return nil
+}
+
+func Test_ParseWithReport_UnformattableValue(t *testing.T) {
+	type configuration struct {
+		Name unmarshalOnlyText `key:"name"`
+	}
+
+	c, report, err := env.ParseWithReport[configuration](env.WithBytes([]byte("NAME=value")))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "value", c.Name.text)
+
+	provenance, ok := report.Lookup("NAME")
+	if assert.True(t, ok) && assert.NotNil(t, provenance.Value) {
+		assert.Equal(t, "{value}", *provenance.Value)
+	}
+}
+
+func Test_Report_StructDefault(t *testing.T) {
+	report := &env.Report{}
+	c := provenanceConfiguration{Host: "localhost", Port: 5}
+	err := yagcl.New[provenanceConfiguration]().
+		Add(env.Source().String("HOST=example.com").Report(report)).
+		Parse(&c)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	host, _ := report.Lookup("HOST")
+	assert.True(t, host.Found)
+	assert.False(t, host.StructDefault)
+
+	port, _ := report.Lookup("PORT")
+	assert.False(t, port.Found)
+	assert.True(t, port.StructDefault)
+	assert.Equal(t, "5", *port.Value)
+
+	name, _ := report.Lookup("DATABASE_NAME")
+	assert.False(t, name.StructDefault)
+
+	assert.Equal(t, ""+
+		"FIELD          KEY            VALUE        ORIGIN\n"+
+		"Host           HOST           example.com  bytes line 1\n"+
+		"Timeout        TIMEOUT        30s          default tag\n"+
+		"Port           PORT           5            struct default\n"+
+		"Password       PASSWORD       REDACTED     not found\n"+
+		"Database.Name  DATABASE_NAME               not found\n",
+		report.String())
+}
@@ -0,0 +1,134 @@
+//go:build !windows && !plan9
+
+package env
+
+import (
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type reloadConfiguration struct {
+	Port int `key:"port"`
+}
+
+func parseReloadConfiguration() (reloadConfiguration, error) {
+	return env.Parse[reloadConfiguration](env.WithPrefix("RELOAD"))
+}
+
+func Test_ReloadOnSignal(t *testing.T) {
+	t.Setenv("RELOAD_PORT", "1")
+	reloader, err := env.ReloadOnSignal(parseReloadConfiguration)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer reloader.Stop()
+	assert.Equal(t, reloadConfiguration{Port: 1}, *reloader.Load())
+
+	reloads := make(chan env.Reload[reloadConfiguration], 1)
+	reloader.OnReload(func(reload env.Reload[reloadConfiguration]) {
+		reloads <- reload
+	})
+
+	t.Setenv("RELOAD_PORT", "2")
+	if !assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP)) {
+		return
+	}
+
+	reload := awaitReload(t, reloads)
+	if assert.NoError(t, reload.Err) {
+		assert.Equal(t, reloadConfiguration{Port: 1}, reload.Old)
+		assert.Equal(t, reloadConfiguration{Port: 2}, reload.New)
+		assert.Equal(t, reloadConfiguration{Port: 2}, *reloader.Load())
+	}
+}
+
+func Test_ReloadOnSignal_CustomSignal(t *testing.T) {
+	t.Setenv("RELOAD_PORT", "1")
+	reloader, err := env.ReloadOnSignal(parseReloadConfiguration, syscall.SIGUSR1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer reloader.Stop()
+
+	reloads := make(chan env.Reload[reloadConfiguration], 1)
+	reloader.OnReload(func(reload env.Reload[reloadConfiguration]) {
+		reloads <- reload
+	})
+
+	t.Setenv("RELOAD_PORT", "3")
+	if !assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1)) {
+		return
+	}
+	awaitReload(t, reloads)
+	assert.Equal(t, reloadConfiguration{Port: 3}, *reloader.Load())
+}
+
+func Test_ReloadOnSignal_FailedReloadKeepsConfiguration(t *testing.T) {
+	t.Setenv("RELOAD_PORT", "1")
+	reloader, err := env.ReloadOnSignal(parseReloadConfiguration)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer reloader.Stop()
+
+	var reported error
+	reloader.OnReload(func(reload env.Reload[reloadConfiguration]) {
+		reported = reload.Err
+	})
+
+	t.Setenv("RELOAD_PORT", "invalid")
+	err = reloader.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, err, reported)
+	assert.Equal(t, reloadConfiguration{Port: 1}, *reloader.Load())
+}
+
+func Test_ReloadOnSignal_Stop(t *testing.T) {
+	t.Setenv("RELOAD_PORT", "1")
+	var parses int32
+	reloader, err := env.ReloadOnSignal(func() (reloadConfiguration, error) {
+		atomic.AddInt32(&parses, 1)
+		return parseReloadConfiguration()
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	reloads := make(chan env.Reload[reloadConfiguration], 1)
+	reloader.OnReload(func(reload env.Reload[reloadConfiguration]) {
+		reloads <- reload
+	})
+	reloader.Stop()
+	reloader.Stop()
+
+	// Without any listener, SIGHUP would terminate the test. This also tells
+	// us when the signal has been delivered.
+	delivered := make(chan os.Signal, 1)
+	signal.Notify(delivered, syscall.SIGHUP)
+	defer signal.Stop(delivered)
+
+	t.Setenv("RELOAD_PORT", "2")
+	if !assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP)) {
+		return
+	}
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal wasn't delivered")
+	}
+
+	select {
+	case <-reloads:
+		t.Fatal("unexpected reload after stopping")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&parses))
+	assert.Equal(t, reloadConfiguration{Port: 1}, *reloader.Load())
+}
@@ -0,0 +1,157 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaProperty struct {
+	Type      string   `json:"type"`
+	Pattern   string   `json:"pattern"`
+	Enum      []string `json:"enum"`
+	Default   *string  `json:"default"`
+	WriteOnly bool     `json:"writeOnly"`
+}
+
+type schema struct {
+	Schema               string                    `json:"$schema"`
+	Properties           map[string]schemaProperty `json:"properties"`
+	PatternProperties    map[string]bool           `json:"patternProperties"`
+	AdditionalProperties bool                      `json:"additionalProperties"`
+	Required             []string                  `json:"required"`
+}
+
+func writeSchema(t *testing.T, configurationStruct any, opts ...env.Option) schema {
+	var buffer bytes.Buffer
+	if !assert.NoError(t, env.WriteJSONSchema(&buffer, configurationStruct, opts...)) {
+		t.FailNow()
+	}
+	var s schema
+	if !assert.NoError(t, json.Unmarshal(buffer.Bytes(), &s)) {
+		t.FailNow()
+	}
+	return s
+}
+
+func Test_WriteJSONSchema(t *testing.T) {
+	type configuration struct {
+		Port     uint16         `key:"port" default:"8080"`
+		Offset   int            `env:"OFFSET,required"`
+		Ratio    float64        `key:"ratio"`
+		Debug    bool           `key:"debug"`
+		Timeout  time.Duration  `key:"timeout"`
+		LogLevel string         `key:"log_level" oneof:"debug,info"`
+		Mode     string         `key:"mode" oneof:"a,b" ignorecase:"true"`
+		Ports    []int          `key:"ports"`
+		Regions  []string       `key:"regions" oneof:"eu,us"`
+		Weights  map[string]int `key:"weights"`
+		Key      [2]byte        `key:"key" encoding:"hex"`
+		Name     string         `key:"name" required:"true"`
+		Password string         `key:"password" secret:"true" default:"hunter2"`
+	}
+
+	s := writeSchema(t, configuration{}, env.WithPrefix("APP"))
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", s.Schema)
+	assert.Equal(t, map[string]bool{"^(?!APP_)": true}, s.PatternProperties)
+	assert.False(t, s.AdditionalProperties)
+	assert.Equal(t, []string{"APP_OFFSET", "APP_NAME"}, s.Required)
+	assert.Len(t, s.Properties, 13)
+	assert.Equal(t, []string{"debug", "info"}, s.Properties["APP_LOG_LEVEL"].Enum)
+	assert.Equal(t, "8080", *s.Properties["APP_PORT"].Default)
+	assert.Nil(t, s.Properties["APP_PASSWORD"].Default)
+	assert.True(t, s.Properties["APP_PASSWORD"].WriteOnly)
+
+	matches := map[string][]string{
+		"APP_PORT":    {"8080"},
+		"APP_OFFSET":  {"-5", "+3"},
+		"APP_RATIO":   {"0.5", "1e+06", "-1"},
+		"APP_DEBUG":   {"true", "FALSE"},
+		"APP_TIMEOUT": {"1m30s", "0", "1.5h"},
+		"APP_MODE":    {"a", "B"},
+		"APP_PORTS":   {"", "1", "1,2,3", "1,"},
+		"APP_REGIONS": {"eu", "eu,us"},
+		"APP_WEIGHTS": {"", "a=1", "a=1,we\\\\=ird=2"},
+		"APP_KEY":     {"00ff"},
+	}
+	mismatches := map[string][]string{
+		"APP_PORT":    {"-1", "a"},
+		"APP_RATIO":   {"1.", "NaN"},
+		"APP_DEBUG":   {"yes", "truefalse"},
+		"APP_TIMEOUT": {"10", "1d"},
+		"APP_MODE":    {"c"},
+		"APP_PORTS":   {"1,,", ",1", "a"},
+		"APP_REGIONS": {"eu,asia"},
+		"APP_WEIGHTS": {"a", "a=b", "a=1=2"},
+		"APP_KEY":     {"00", "00fff"},
+	}
+	for key, values := range matches {
+		pattern := regexp.MustCompile(s.Properties[key].Pattern)
+		for _, value := range values {
+			assert.True(t, pattern.MatchString(value), "%s should match %s", value, key)
+		}
+	}
+	for key, values := range mismatches {
+		pattern := regexp.MustCompile(s.Properties[key].Pattern)
+		for _, value := range values {
+			assert.False(t, pattern.MatchString(value), "%s shouldn't match %s", value, key)
+		}
+	}
+}
+
+func Test_WriteJSONSchema_NoPrefix(t *testing.T) {
+	type configuration struct {
+		Name string `key:"name"`
+	}
+
+	s := writeSchema(t, configuration{})
+	assert.Nil(t, s.PatternProperties)
+	assert.False(t, s.AdditionalProperties)
+	assert.Equal(t, "string", s.Properties["NAME"].Type)
+	assert.Empty(t, s.Properties["NAME"].Pattern)
+}
+
+func Test_WriteJSONSchema_MatchesDecoder(t *testing.T) {
+	type configuration struct {
+		Ratio   float64           `key:"ratio"`
+		Ratios  []float64         `key:"ratios"`
+		Ports   []int             `key:"ports"`
+		Pair    [2]int            `key:"pair"`
+		Names   []string          `key:"names" oneof:"a,b\\,c"`
+		Labels  map[string]string `key:"labels"`
+		Weights map[string]int    `key:"weights"`
+		Modes   map[string]string `key:"modes" oneof:"x=y,z"`
+		Debug   bool              `key:"debug"`
+		Timeout time.Duration     `key:"timeout"`
+	}
+
+	inputs := map[string][]string{
+		"RATIO":   {"0.5", " 1.5 ", "\t-1e3\n", "null", " null ", "", "1.", "NaN", "0x10", "nul", "1 2"},
+		"RATIOS":  {"", "1, 2 ,null", "1,", "1,,", ",1", "1,x"},
+		"PORTS":   {"", "1", "1,2", "1,2,", "1,2,,", ",", "1,x"},
+		"PAIR":    {"1,2", "1,2,", "1", "1,2,3", "1,,"},
+		"NAMES":   {"", "a", "a,", "b\\,c", "a,b\\,c,", "b", "a,,"},
+		"LABELS":  {"", "a=b", "a=b,", "a=b,,", ",", "a=b=c", "a\\=b=c", "a\\=b", "a\\\\=b=c", "a\\\\=b", "a\\,b=c", "a\\\\\\=b=c", "a=", "a==", "=b", "==", "a", "a=b,c==,"},
+		"WEIGHTS": {"", "a=1", "a=1,", "a=", "a==", "a=1=2", "a=x"},
+		"MODES":   {"k=x\\\\=y", "k=z", "k=x=y", "k=x", "k=z,"},
+		"DEBUG":   {"true", "False", "yes", ""},
+		"TIMEOUT": {"1m30s", "0", "10", ""},
+	}
+
+	s := writeSchema(t, configuration{})
+	for key, values := range inputs {
+		pattern := regexp.MustCompile(s.Properties[key].Pattern)
+		for _, value := range values {
+			t.Setenv(key, value)
+			_, err := env.Parse[configuration]()
+			assert.Equal(t, err == nil, pattern.MatchString(value), "key %s, value %q, error %v", key, value, err)
+		}
+		os.Unsetenv(key)
+	}
+}
@@ -966,6 +966,43 @@ func Test_Parse_IntSlice(t *testing.T) {
 	}
 }
 
+func Test_Parse_IntSlice_TrailingSeparator(t *testing.T) {
+	type config struct {
+		Field []int `key:"field"`
+	}
+
+	t.Setenv("FIELD", "1,2,")
+	var c config
+	err := yagcl.New[config]().
+		Add(env.Source().Env()).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int{1, 2}, c.Field)
+	}
+}
+
+func Test_Parse_StringSlice_TrailingEmptyElement(t *testing.T) {
+	type config struct {
+		Field []string `key:"field"`
+	}
+
+	for value, expected := range map[string][]string{
+		"a,":  {"a"},
+		"a,,": {"a", ""},
+		",":   {""},
+		",a":  {"", "a"},
+	} {
+		t.Setenv("FIELD", value)
+		var c config
+		err := yagcl.New[config]().
+			Add(env.Source().Env()).
+			Parse(&c)
+		if assert.NoError(t, err, value) {
+			assert.Equal(t, expected, c.Field, value)
+		}
+	}
+}
+
 func Test_Parse_SliceInvalidValue(t *testing.T) {
 	type config struct {
 		Field []int `key:"field"`
@@ -1255,6 +1292,34 @@ func Test_Parse_Map_IntInt_FormatError(t *testing.T) {
 	assert.ErrorIs(t, err, yagcl.ErrParseValue)
 }
 
+func Test_Parse_Map_TrailingSeparator(t *testing.T) {
+	type config struct {
+		Field map[string]string `key:"field"`
+	}
+	t.Setenv("FIELD", "a=b,")
+	var c config
+	err := yagcl.New[config]().
+		Add(env.Source().Env()).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"a": "b"}, c.Field)
+	}
+}
+
+func Test_Parse_Map_EmptyEntry(t *testing.T) {
+	type config struct {
+		Field map[string]string `key:"field"`
+	}
+	for _, value := range []string{",", "a=b,,", ",a=b"} {
+		t.Setenv("FIELD", value)
+		var c config
+		err := yagcl.New[config]().
+			Add(env.Source().Env()).
+			Parse(&c)
+		assert.ErrorIs(t, err, yagcl.ErrParseValue, value)
+	}
+}
+
 func Test_Parse_Map_StringEscaping(t *testing.T) {
 	type config struct {
 		Field map[int]string `key:"field"`
@@ -1314,3 +1379,188 @@ func Test_Parse_IntArray_EmptyValues(t *testing.T) {
 	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
 	assert.Error(t, err)
 }
+
+func Test_Parse_ByteSlice_WithoutEncoding(t *testing.T) {
+	type configuration struct {
+		FieldA []byte   `key:"field_a"`
+		FieldB [3]uint8 `key:"field_b"`
+	}
+
+	t.Setenv("FIELD_A", "1,2,3")
+	t.Setenv("FIELD_B", "4,5,6")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{1, 2, 3}, c.FieldA)
+		assert.Equal(t, [3]uint8{4, 5, 6}, c.FieldB)
+	}
+}
+
+func Test_Parse_ByteSlice_Raw(t *testing.T) {
+	type configuration struct {
+		FieldA []byte `key:"field_a" encoding:"raw"`
+		FieldB []byte `key:"field_b" encoding:""`
+	}
+
+	t.Setenv("FIELD_B", "4,5")
+
+	t.Setenv("FIELD_A", "1,2,3")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte("1,2,3"), c.FieldA)
+		assert.Equal(t, []byte("4,5"), c.FieldB)
+	}
+}
+
+func Test_Parse_ByteSlice_Encodings(t *testing.T) {
+	type configuration struct {
+		Base64    []byte  `key:"base64" encoding:"base64"`
+		Base64URL []byte  `key:"base64url" encoding:"base64url"`
+		Hex       []byte  `key:"hex" encoding:"hex"`
+		Pointer   *[]byte `key:"pointer" encoding:"hex"`
+	}
+
+	t.Setenv("BASE64", "/+8=")
+	t.Setenv("BASE64URL", "_-8")
+	t.Setenv("HEX", "ffef")
+	t.Setenv("POINTER", "ffef")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0xff, 0xef}, c.Base64)
+		assert.Equal(t, []byte{0xff, 0xef}, c.Base64URL)
+		assert.Equal(t, []byte{0xff, 0xef}, c.Hex)
+		assert.Equal(t, []byte{0xff, 0xef}, *c.Pointer)
+	}
+}
+
+func Test_Parse_ByteSlice_InvalidData(t *testing.T) {
+	type configuration struct {
+		FieldA []byte `key:"field_a" encoding:"hex"`
+	}
+
+	t.Setenv("FIELD_A", "not hex")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+}
+
+func Test_Parse_ByteSlice_UnsupportedEncoding(t *testing.T) {
+	type configuration struct {
+		FieldA []byte `key:"field_a" encoding:"rot13"`
+	}
+
+	t.Setenv("FIELD_A", "value")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.ErrorIs(t, err, env.ErrUnsupportedByteEncoding)
+}
+
+func Test_Parse_ByteArray(t *testing.T) {
+	type configuration struct {
+		Key [4]byte `key:"key" encoding:"hex"`
+	}
+
+	t.Setenv("KEY", "00010203")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, [4]byte{0, 1, 2, 3}, c.Key)
+	}
+}
+
+func Test_Parse_ByteArray_IncorrectLength(t *testing.T) {
+	type configuration struct {
+		Key [32]byte `key:"key" encoding:"hex"`
+	}
+
+	t.Setenv("KEY", "00010203")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+}
+
+func Test_Parse_SimplePointer_NotSet(t *testing.T) {
+	type configuration struct {
+		FieldA *uint `key:"field_a"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Nil(t, c.FieldA)
+	}
+}
+
+func Test_Parse_DefaultTag(t *testing.T) {
+	type configuration struct {
+		Timeout time.Duration           `key:"timeout" default:"30s"`
+		Hosts   []string                `key:"hosts" default:"a,b"`
+		Port    *int                    `key:"port" default:"8080"`
+		Custom  customTextUnmarshalable `key:"custom" default:"value"`
+		Nested  struct {
+			Name string `key:"name" default:"nested"`
+		} `key:"nested"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 30*time.Second, c.Timeout)
+		assert.Equal(t, []string{"a", "b"}, c.Hosts)
+		assert.Equal(t, 8080, *c.Port)
+		assert.Equal(t, customTextUnmarshalable("VALUE"), c.Custom)
+		assert.Equal(t, "nested", c.Nested.Name)
+	}
+}
+
+func Test_Parse_DefaultTag_KeySet(t *testing.T) {
+	type configuration struct {
+		Timeout time.Duration `key:"timeout" default:"30s"`
+	}
+
+	t.Setenv("TIMEOUT", "10s")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 10*time.Second, c.Timeout)
+	}
+}
+
+func Test_Parse_DefaultTag_PreserveDefaults(t *testing.T) {
+	type configuration struct {
+		Timeout time.Duration `key:"timeout" default:"30s"`
+	}
+
+	c := configuration{Timeout: time.Minute}
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Minute, c.Timeout)
+	}
+}
+
+func Test_Parse_DefaultTag_Invalid(t *testing.T) {
+	type configuration struct {
+		Timeout time.Duration `key:"timeout" default:"thirty seconds"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.ErrorIs(t, err, yagcl.ErrParseValue) {
+		assert.Contains(t, err.Error(), "Timeout")
+	}
+}
+
+func Test_Parse_StringSlice_MultiByteLastCharacter(t *testing.T) {
+	type configuration struct {
+		FieldA []string `key:"field_a"`
+	}
+
+	t.Setenv("FIELD_A", "a,ä")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"a", "ä"}, c.FieldA)
+	}
+}
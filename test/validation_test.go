@@ -0,0 +1,244 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_OneOf_Valid(t *testing.T) {
+	type configuration struct {
+		LogLevel string `key:"log_level" oneof:"debug,info,warn,error"`
+	}
+
+	t.Setenv("LOG_LEVEL", "warn")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "warn", c.LogLevel)
+	}
+}
+
+func Test_Parse_OneOf_Invalid(t *testing.T) {
+	type configuration struct {
+		LogLevel string `key:"log_level" oneof:"debug,info,warn,error"`
+	}
+
+	t.Setenv("LOG_LEVEL", "WARN")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.ErrorIs(t, err, env.ErrValueNotAllowed) {
+		assert.Contains(t, err.Error(), "LOG_LEVEL")
+		assert.Contains(t, err.Error(), "[debug, info, warn, error]")
+	}
+}
+
+func Test_Parse_OneOf_IgnoreCase(t *testing.T) {
+	type configuration struct {
+		LogLevel string `key:"log_level" oneof:"debug,info,warn,error" ignorecase:"true"`
+	}
+
+	t.Setenv("LOG_LEVEL", "WARN")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "WARN", c.LogLevel)
+	}
+}
+
+func Test_Parse_OneOf_IntAlias(t *testing.T) {
+	type mode int
+	type configuration struct {
+		Mode *mode `key:"mode" oneof:"1,2,4"`
+	}
+
+	t.Setenv("MODE", "2")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, mode(2), *c.Mode)
+	}
+
+	t.Setenv("MODE", "3")
+	err = yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.ErrorIs(t, err, env.ErrValueNotAllowed)
+}
+
+func Test_Parse_OneOf_Slice(t *testing.T) {
+	type configuration struct {
+		Regions []string `key:"regions" oneof:"eu,us"`
+	}
+
+	t.Setenv("REGIONS", "eu,us")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"eu", "us"}, c.Regions)
+	}
+
+	t.Setenv("REGIONS", "eu,asia")
+	err = yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.ErrorIs(t, err, env.ErrValueNotAllowed)
+}
+
+func Test_Parse_OneOf_Map(t *testing.T) {
+	type configuration struct {
+		Levels map[string]string `key:"levels" oneof:"debug,info"`
+	}
+
+	t.Setenv("LEVELS", "http=debug,db=info")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.NoError(t, err)
+
+	t.Setenv("LEVELS", "http=debug,db=trace")
+	err = yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.ErrorIs(t, err, env.ErrValueNotAllowed)
+}
+
+func Test_Parse_OneOf_NotSet(t *testing.T) {
+	type configuration struct {
+		LogLevel string `key:"log_level" oneof:"debug,info"`
+	}
+
+	c := configuration{LogLevel: "custom default"}
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "custom default", c.LogLevel)
+	}
+}
+
+func Test_Parse_MinMax(t *testing.T) {
+	type configuration struct {
+		Port    uint16        `key:"port" min:"1"`
+		Workers int           `key:"workers" min:"1" max:"64"`
+		Timeout time.Duration `key:"timeout" max:"1m"`
+		Ratio   float64       `key:"ratio" min:"0" max:"1"`
+	}
+
+	t.Setenv("PORT", "8080")
+	t.Setenv("WORKERS", "64")
+	t.Setenv("TIMEOUT", "30s")
+	t.Setenv("RATIO", "0.5")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.NoError(t, err)
+
+	t.Setenv("PORT", "0")
+	t.Setenv("WORKERS", "-5")
+	t.Setenv("TIMEOUT", "2m")
+	t.Setenv("RATIO", "1.5")
+	c = configuration{}
+	err = yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	if assert.ErrorIs(t, err, env.ErrValidation) {
+		var violations env.ValidationErrors
+		if assert.ErrorAs(t, err, &violations) && assert.Len(t, violations, 4) {
+			assert.Equal(t, "PORT", violations[0].Key)
+			assert.Equal(t, "min", violations[0].Rule)
+			assert.Equal(t, "WORKERS", violations[1].Key)
+			assert.Equal(t, "TIMEOUT", violations[2].Key)
+			assert.Equal(t, "max", violations[2].Rule)
+			assert.Equal(t, "RATIO", violations[3].Key)
+		}
+	}
+}
+
+func Test_Parse_MinMax_InvalidBound(t *testing.T) {
+	type configuration struct {
+		Port int `key:"port" min:"one"`
+	}
+
+	t.Setenv("PORT", "1")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+	assert.NotErrorIs(t, err, env.ErrValidation)
+}
+
+func Test_Parse_InvalidRules_KeyAbsent(t *testing.T) {
+	// Invalid rule definitions are detected when compiling the plan, no
+	// matter whether the key is present.
+	type invalidBound struct {
+		Ports []int `key:"ports" max:"one"`
+	}
+	_, err := env.Parse[invalidBound](env.WithBytes(nil))
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+
+	type boundOnString struct {
+		Host string `key:"host" min:"1"`
+	}
+	_, err = env.Parse[boundOnString](env.WithBytes(nil))
+	assert.ErrorIs(t, err, yagcl.ErrUnsupportedFieldType)
+
+	type patternOnInt struct {
+		Port int `key:"port" pattern:"^[0-9]+$"`
+	}
+	_, err = env.Parse[patternOnInt](env.WithBytes(nil))
+	assert.ErrorIs(t, err, yagcl.ErrUnsupportedFieldType)
+}
+
+func Test_Parse_Length(t *testing.T) {
+	type configuration struct {
+		Name  string         `key:"name" minlen:"2" maxlen:"4"`
+		Hosts []string       `key:"hosts" minlen:"1"`
+		Tags  map[string]int `key:"tags" maxlen:"1"`
+	}
+
+	t.Setenv("NAME", "äöü")
+	t.Setenv("HOSTS", "a")
+	t.Setenv("TAGS", "a=1")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.NoError(t, err)
+
+	t.Setenv("NAME", "abcde")
+	t.Setenv("TAGS", "a=1,b=2")
+	err = yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	var violations env.ValidationErrors
+	if assert.ErrorAs(t, err, &violations) && assert.Len(t, violations, 2) {
+		assert.Equal(t, "NAME", violations[0].Key)
+		assert.Equal(t, "maxlen", violations[0].Rule)
+		assert.Equal(t, "TAGS", violations[1].Key)
+	}
+}
+
+func Test_Parse_Pattern(t *testing.T) {
+	type configuration struct {
+		Nested struct {
+			Region string `key:"region" pattern:"^[a-z]{2}-[a-z]+-[0-9]$"`
+		} `key:"nested"`
+	}
+
+	t.Setenv("NESTED_REGION", "eu-west-1")
+	var c configuration
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.NoError(t, err)
+
+	t.Setenv("NESTED_REGION", "EU-WEST-1")
+	err = yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	var violations env.ValidationErrors
+	if assert.ErrorAs(t, err, &violations) && assert.Len(t, violations, 1) {
+		assert.Equal(t, "NESTED_REGION", violations[0].Key)
+		assert.Equal(t, "pattern", violations[0].Rule)
+	}
+}
+
+func Test_Parse_Required(t *testing.T) {
+	type configuration struct {
+		Host string `key:"host" required:"true"`
+		Port int    `key:"port" required:"true"`
+	}
+
+	t.Setenv("HOST", "localhost")
+	c := configuration{Port: 8080}
+	err := yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.NoError(t, err)
+
+	c = configuration{}
+	err = yagcl.New[configuration]().Add(env.Source().Env()).Parse(&c)
+	assert.ErrorIs(t, err, yagcl.ErrValueNotSet)
+	assert.ErrorIs(t, err, env.ErrValidation)
+}
@@ -0,0 +1,120 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	env "github.com/Bios-Marcel/yagcl-env"
+	"github.com/stretchr/testify/assert"
+)
+
+type watchConfiguration struct {
+	Port int `key:"port"`
+}
+
+func startWatching(t *testing.T, content string, opts ...env.WatchOption) (string, *env.Watcher[watchConfiguration], chan env.Reload[watchConfiguration]) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reloads := make(chan env.Reload[watchConfiguration], 10)
+	watcher, err := env.Watch(func() (watchConfiguration, error) {
+		return env.Parse[watchConfiguration](env.WithPath(path), env.WithMust())
+	}, func(reload env.Reload[watchConfiguration]) {
+		reloads <- reload
+	}, []string{path}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		assert.NoError(t, watcher.Close())
+	})
+	return path, watcher, reloads
+}
+
+// replaceFile replaces the file via rename, just like many editors do when
+// saving. This also prevents the watcher from seeing partial writes.
+func replaceFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	temporaryPath := path + ".tmp"
+	if err := os.WriteFile(temporaryPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(temporaryPath, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func awaitReload[T any](t *testing.T, reloads chan env.Reload[T]) env.Reload[T] {
+	t.Helper()
+
+	select {
+	case reload := <-reloads:
+		return reload
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+		return env.Reload[T]{}
+	}
+}
+
+func Test_Watch_RenameAndReplace(t *testing.T) {
+	path, watcher, reloads := startWatching(t, "PORT=1")
+	assert.Equal(t, watchConfiguration{Port: 1}, watcher.Current())
+
+	replaceFile(t, path, "PORT=2")
+
+	reload := awaitReload(t, reloads)
+	if assert.NoError(t, reload.Err) {
+		assert.Equal(t, watchConfiguration{Port: 1}, reload.Old)
+		assert.Equal(t, watchConfiguration{Port: 2}, reload.New)
+		assert.Equal(t, watchConfiguration{Port: 2}, watcher.Current())
+	}
+}
+
+func Test_Watch_Polling(t *testing.T) {
+	path, watcher, reloads := startWatching(t, "PORT=1", env.WithPolling(), env.WithPollInterval(10*time.Millisecond))
+
+	replaceFile(t, path, "PORT=2")
+
+	reload := awaitReload(t, reloads)
+	if assert.NoError(t, reload.Err) {
+		assert.Equal(t, watchConfiguration{Port: 2}, reload.New)
+		assert.Equal(t, watchConfiguration{Port: 2}, watcher.Current())
+	}
+}
+
+func Test_Watch_FailedReloadKeepsConfiguration(t *testing.T) {
+	path, watcher, reloads := startWatching(t, "PORT=1")
+
+	replaceFile(t, path, "PORT=invalid")
+	reload := awaitReload(t, reloads)
+	assert.Error(t, reload.Err)
+	assert.Equal(t, watchConfiguration{Port: 1}, reload.Old)
+	assert.Equal(t, watchConfiguration{Port: 1}, watcher.Current())
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	reload = awaitReload(t, reloads)
+	assert.Error(t, reload.Err)
+	assert.Equal(t, watchConfiguration{Port: 1}, watcher.Current())
+
+	replaceFile(t, path, "PORT=3")
+	reload = awaitReload(t, reloads)
+	if assert.NoError(t, reload.Err) {
+		assert.Equal(t, watchConfiguration{Port: 3}, watcher.Current())
+	}
+}
+
+func Test_Watch_InitialParseFails(t *testing.T) {
+	_, err := env.Watch(func() (watchConfiguration, error) {
+		return env.Parse[watchConfiguration](env.WithPath("doesntexist.env"), env.WithMust())
+	}, nil, []string{"doesntexist.env"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,423 @@
+package env
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// ErrValidation is the error all validation errors can be matched against
+// using errors.Is.
+var ErrValidation = errors.New("validation failed")
+
+// ErrValueNotAllowed is thrown if a value isn't part of the list of values
+// allowed by the `oneof` tag.
+var ErrValueNotAllowed = errors.New("value not allowed")
+
+const (
+	// oneOfTagName is the tag containing a comma separated list of allowed
+	// values. Commas can be escaped the same way as in slice values.
+	oneOfTagName = "oneof"
+	// ignoreCaseTagName can be set to `true` in order to compare values
+	// defined via `oneof` case-insensitively.
+	ignoreCaseTagName = "ignorecase"
+	// minTagName defines the inclusive lower bound of a numeric value.
+	minTagName = "min"
+	// maxTagName defines the inclusive upper bound of a numeric value.
+	maxTagName = "max"
+	// minLenTagName defines the minimum amount of characters of a string or
+	// the minimum amount of elements of a slice, array or map.
+	minLenTagName = "minlen"
+	// maxLenTagName defines the maximum amount of characters of a string or
+	// the maximum amount of elements of a slice, array or map.
+	maxLenTagName = "maxlen"
+	// patternTagName defines a regular expression string values have to
+	// match. The expression isn't anchored implicitly.
+	patternTagName = "pattern"
+	// requiredTagName can be set to `true` in order to require the field to
+	// have a non-zero value after parsing. Defaults count as values.
+	requiredTagName = "required"
+)
+
+// ValidationError describes a single validation rule violated by a field.
+type ValidationError struct {
+	// Key is the fully joined environment variable key of the field.
+	Key string
+	// Rule is the name of the tag defining the violated rule.
+	Rule string
+	// Reason is a human readable description of the violation.
+	Reason string
+
+	// sentinel is an optional, more specific error that can be matched.
+	sentinel error
+}
+
+// Error implements error.Error.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("field '%s' violates rule '%s': %s", e.Key, e.Rule, e.Reason)
+}
+
+// Is allows matching against ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Unwrap returns the rule specific error, such as ErrValueNotAllowed or
+// yagcl.ErrValueNotSet, if there's one.
+func (e *ValidationError) Unwrap() error {
+	return e.sentinel
+}
+
+// ValidationErrors aggregates all validation errors that occurred during a
+// single call to Parse.
+type ValidationErrors []*ValidationError
+
+// Error implements error.Error.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether any of the contained errors matches the target.
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// validationRules are the rules defined via tags on a single field. They are
+// compiled once per field, so that invalid rule definitions are detected
+// early and patterns aren't compiled repeatedly.
+type validationRules struct {
+	fieldName string
+	// binary is true for []byte and [N]byte fields with an encoding, which
+	// are validated as a single value.
+	binary bool
+
+	required bool
+
+	oneOf         bool
+	allowedValues []string
+	ignoreCase    bool
+
+	// min and max are parsed using the type of the elements. The raw values
+	// are kept for error messages.
+	min, max       *reflect.Value
+	rawMin, rawMax string
+
+	minLength, maxLength *int
+
+	rawPattern string
+	pattern    *regexp.Regexp
+}
+
+// compileValidationRules creates the rules defined via tags on the given
+// field. If no rules are defined, nil is returned. Errors indicate invalid
+// rule definitions, which are programming errors.
+func compileValidationRules(structField reflect.StructField) (*validationRules, error) {
+	rules := validationRules{
+		fieldName: structField.Name,
+		binary:    isBinary(extractNonPointerFieldType(structField.Type), byteEncodingOf(structField)),
+		required:  strings.EqualFold(structField.Tag.Get(requiredTagName), "true"),
+	}
+	hasRules := rules.required
+	elementType := elementTypeOf(structField.Type, rules.binary)
+
+	if rawAllowedValues, ok := structField.Tag.Lookup(oneOfTagName); ok {
+		rules.oneOf = true
+		rules.allowedValues = splitString(rawAllowedValues, ',')
+		rules.ignoreCase = strings.EqualFold(structField.Tag.Get(ignoreCaseTagName), "true")
+		hasRules = true
+	}
+
+	if rawBound, ok := structField.Tag.Lookup(minTagName); ok {
+		bound, errParse := parseBound(structField.Name, elementType, rawBound)
+		if errParse != nil {
+			return nil, errParse
+		}
+		rules.min, rules.rawMin = &bound, rawBound
+		hasRules = true
+	}
+	if rawBound, ok := structField.Tag.Lookup(maxTagName); ok {
+		bound, errParse := parseBound(structField.Name, elementType, rawBound)
+		if errParse != nil {
+			return nil, errParse
+		}
+		rules.max, rules.rawMax = &bound, rawBound
+		hasRules = true
+	}
+
+	for _, rule := range []string{minLenTagName, maxLenTagName} {
+		rawLength, ok := structField.Tag.Lookup(rule)
+		if !ok {
+			continue
+		}
+		limit, errParse := parseValue(structField.Name, reflect.TypeOf(0), "", rawLength)
+		if errParse != nil {
+			return nil, fmt.Errorf("tag '%s' of field '%s' must be an integer: %w", rule, structField.Name, errParse)
+		}
+		switch extractNonPointerFieldType(structField.Type).Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		default:
+			return nil, fmt.Errorf("tag '%s' can't be used on field '%s' of type '%s': %w", rule, structField.Name, structField.Type, yagcl.ErrUnsupportedFieldType)
+		}
+
+		length := int(limit.Int())
+		if rule == minLenTagName {
+			rules.minLength = &length
+		} else {
+			rules.maxLength = &length
+		}
+		hasRules = true
+	}
+
+	if rawPattern, ok := structField.Tag.Lookup(patternTagName); ok {
+		if elementType.Kind() != reflect.String {
+			return nil, fmt.Errorf("tag '%s' can't be used on field '%s' of type '%s': %w", patternTagName, structField.Name, structField.Type, yagcl.ErrUnsupportedFieldType)
+		}
+		pattern, errCompile := regexp.Compile(rawPattern)
+		if errCompile != nil {
+			return nil, fmt.Errorf("tag '%s' of field '%s' isn't a valid regular expression: %w", patternTagName, structField.Name, errCompile)
+		}
+		rules.rawPattern = rawPattern
+		rules.pattern = pattern
+		hasRules = true
+	}
+
+	if !hasRules {
+		return nil, nil
+	}
+	return &rules, nil
+}
+
+// elementTypeOf returns the type of the values forEachElement passes on for
+// fields of the given type.
+func elementTypeOf(fieldType reflect.Type, binary bool) reflect.Type {
+	fieldType = extractNonPointerFieldType(fieldType)
+	if binary {
+		return fieldType
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return elementTypeOf(fieldType.Elem(), false)
+	}
+	return fieldType
+}
+
+// parseBound parses the value of the `min` or `max` tag using the given
+// element type, which has to be numeric.
+func parseBound(fieldName string, elementType reflect.Type, rawBound string) (reflect.Value, error) {
+	switch elementType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return reflect.Value{}, fmt.Errorf("bounds can't be used on field '%s' of type '%s': %w", fieldName, elementType, yagcl.ErrUnsupportedFieldType)
+	}
+
+	bound, errParse := parseValue(fieldName, elementType, "", rawBound)
+	if errParse != nil {
+		return reflect.Value{}, fmt.Errorf("bound '%s' of field '%s' isn't valid for type '%s': %w", rawBound, fieldName, elementType, errParse)
+	}
+	return bound.Convert(elementType), nil
+}
+
+// validate checks all rules against the given value. The value based rules
+// are only checked if the field was set by this source, while `required` is
+// always checked, since defaults count as values. All rule definitions have
+// already been checked by compileValidationRules.
+func (r *validationRules) validate(joinedEnvKey string, value reflect.Value, set bool) ValidationErrors {
+	if r == nil {
+		return nil
+	}
+
+	var violations ValidationErrors
+	addViolation := func(rule, reason string, sentinel error) {
+		violations = append(violations, &ValidationError{
+			Key:      joinedEnvKey,
+			Rule:     rule,
+			Reason:   reason,
+			sentinel: sentinel,
+		})
+	}
+
+	if r.required && value.IsZero() {
+		addViolation(requiredTagName, "no non-zero value has been set", yagcl.ErrValueNotSet)
+	}
+
+	if !set {
+		return violations
+	}
+
+	if r.oneOf {
+		forEachElement(value, r.binary, func(element reflect.Value) error {
+			for _, allowedValue := range r.allowedValues {
+				if matchesAllowedValue(element, allowedValue, r.ignoreCase) {
+					return nil
+				}
+			}
+
+			addViolation(oneOfTagName, fmt.Sprintf("value '%v' isn't allowed; expected one of [%s]", element.Interface(), strings.Join(r.allowedValues, ", ")), ErrValueNotAllowed)
+			return nil
+		})
+	}
+
+	if r.min != nil || r.max != nil {
+		forEachElement(value, r.binary, func(element reflect.Value) error {
+			if r.min != nil && compareToBound(element, *r.min) < 0 {
+				addViolation(minTagName, fmt.Sprintf("value '%v' is less than %s", element.Interface(), r.rawMin), nil)
+			}
+			if r.max != nil && compareToBound(element, *r.max) > 0 {
+				addViolation(maxTagName, fmt.Sprintf("value '%v' is greater than %s", element.Interface(), r.rawMax), nil)
+			}
+			return nil
+		})
+	}
+
+	if r.minLength != nil || r.maxLength != nil {
+		length, _ := lengthOf(value)
+		if r.minLength != nil && length < *r.minLength {
+			addViolation(minLenTagName, fmt.Sprintf("length %d is less than %d", length, *r.minLength), nil)
+		}
+		if r.maxLength != nil && length > *r.maxLength {
+			addViolation(maxLenTagName, fmt.Sprintf("length %d is greater than %d", length, *r.maxLength), nil)
+		}
+	}
+
+	if r.pattern != nil {
+		forEachElement(value, r.binary, func(element reflect.Value) error {
+			if !r.pattern.MatchString(element.String()) {
+				addViolation(patternTagName, fmt.Sprintf("value '%s' doesn't match pattern '%s'", element.String(), r.rawPattern), nil)
+			}
+			return nil
+		})
+	}
+
+	return violations
+}
+
+// forEachElement calls the given function for the (dereferenced) value, or
+// each element of the value, if it is a slice, array or map. Binary data is
+// treated as a single value. Nil pointers are skipped.
+func forEachElement(value reflect.Value, binary bool, fn func(reflect.Value) error) error {
+	value, ok := dereference(value)
+	if !ok {
+		return nil
+	}
+
+	if binary {
+		return fn(value)
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := forEachElement(value.Index(i), false, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := forEachElement(iter.Value(), false, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fn(value)
+}
+
+// dereference follows all pointers and returns false if a nil pointer has
+// been encountered.
+func dereference(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return value, false
+		}
+		value = value.Elem()
+	}
+	return value, true
+}
+
+// lengthOf returns the amount of characters of a string or the amount of
+// elements of a slice, array or map. A nil pointer has a length of 0.
+func lengthOf(value reflect.Value) (int, bool) {
+	value, ok := dereference(value)
+	if !ok {
+		return 0, true
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(value.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return value.Len(), true
+	}
+	return 0, false
+}
+
+// compareToBound returns -1, 0 or 1, depending on whether the value is less
+// than, equal to or greater than the bound, which has the same type.
+func compareToBound(value, bound reflect.Value) int {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compare(value.Int(), bound.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compare(value.Uint(), bound.Uint())
+	}
+	return compare(value.Float(), bound.Float())
+}
+
+func compare[T int64 | uint64 | float64](a, b T) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+func matchesAllowedValue(value reflect.Value, allowedValue string, ignoreCase bool) bool {
+	equal := func(a, b string) bool {
+		if ignoreCase {
+			return strings.EqualFold(a, b)
+		}
+		return a == b
+	}
+
+	// Custom types are compared by their textual representation, as we can't
+	// know how to otherwise compare them.
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return err == nil && equal(string(text), allowedValue)
+	}
+
+	if value.Kind() == reflect.String {
+		return equal(value.String(), allowedValue)
+	}
+
+	// Non-string values, such as integer aliases are parsed the same way as
+	// the actual value, so that for example "01" and "1" are equal.
+	parsedAllowedValue, err := parseValue("", value.Type(), "", allowedValue)
+	if err != nil || !value.Type().Comparable() || !parsedAllowedValue.Type().ConvertibleTo(value.Type()) {
+		return false
+	}
+	return parsedAllowedValue.Convert(value.Type()).Interface() == value.Interface()
+}
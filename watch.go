@@ -0,0 +1,256 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// errNotifierUnsupported is returned by newNotifier on platforms without
+// native file notifications, causing the watcher to fall back to polling.
+var errNotifierUnsupported = errors.New("file notifications not supported on this platform")
+
+// notifier signals that something in the watched directories has changed.
+// The events channel is closed once the notifier stops working.
+type notifier interface {
+	Events() <-chan struct{}
+	Close() error
+}
+
+// WatchOption configures Watch.
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	pollInterval time.Duration
+	forcePolling bool
+	debounce     time.Duration
+}
+
+// WithPollInterval defines how often files are checked for changes if
+// native file notifications aren't available. The default is one second.
+func WithPollInterval(interval time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// WithPolling forces polling, even if native file notifications are
+// available. This is required for file systems that don't support
+// notifications, such as some network file systems.
+func WithPolling() WatchOption {
+	return func(o *watchOptions) {
+		o.forcePolling = true
+	}
+}
+
+// Reload describes the outcome of re-parsing the configuration after a
+// change. If Err is set, New is the zero value and Old is kept.
+type Reload[T any] struct {
+	Old T
+	New T
+	Err error
+}
+
+// Watcher re-parses a configuration whenever one of the watched files
+// changes.
+type Watcher[T any] struct {
+	parse    func() (T, error)
+	onReload func(Reload[T])
+	paths    []string
+	options  watchOptions
+
+	mutex   sync.RWMutex
+	current T
+	// contents holds the last seen content of each path, nil meaning the
+	// file didn't exist. Files are compared by content, since editors
+	// and tools such as Kubernetes replace files in different ways.
+	contents [][]byte
+
+	notifier notifier
+	done     chan struct{}
+	stopped  chan struct{}
+	close    sync.Once
+}
+
+// Watch parses the configuration using the given function and calls it
+// again each time one of the given files changes, passing the result to
+// onReload. Usually the paths are the ones used by the sources inside the
+// parse function. If reloading fails, the previous configuration is kept.
+//
+// Files are watched via their directories, so that files replaced by
+// renaming, as many editors do, as well as deleted and recreated files are
+// handled. Where native notifications aren't available, files are polled.
+//
+//	watcher, err := env.Watch(func() (Config, error) {
+//		return env.Parse[Config](env.WithPath(".env"))
+//	}, func(reload env.Reload[Config]) {
+//		...
+//	}, []string{".env"})
+func Watch[T any](parse func() (T, error), onReload func(Reload[T]), paths []string, opts ...WatchOption) (*Watcher[T], error) {
+	options := watchOptions{
+		pollInterval: time.Second,
+		debounce:     50 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	w := &Watcher[T]{
+		parse:    parse,
+		onReload: onReload,
+		paths:    make([]string, 0, len(paths)),
+		options:  options,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	for _, path := range paths {
+		absolutePath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		w.paths = append(w.paths, absolutePath)
+	}
+
+	// Reading the contents first makes sure that we don't miss changes
+	// happening during the initial parse.
+	w.contents = w.readContents()
+	current, err := parse()
+	if err != nil {
+		return nil, err
+	}
+	w.current = current
+
+	if !options.forcePolling {
+		directories := make([]string, 0, len(w.paths))
+		seen := make(map[string]bool)
+		for _, path := range w.paths {
+			if directory := filepath.Dir(path); !seen[directory] {
+				seen[directory] = true
+				directories = append(directories, directory)
+			}
+		}
+		// If notifications can't be set up, for example since a directory
+		// doesn't exist, we silently fall back to polling.
+		if notifier, err := newNotifier(directories); err == nil {
+			w.notifier = notifier
+		}
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Current returns the most recently parsed configuration.
+func (w *Watcher[T]) Current() T {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.current
+}
+
+// Close stops watching. It waits for running callbacks to finish and
+// therefore mustn't be called from within the callback.
+func (w *Watcher[T]) Close() error {
+	var err error
+	w.close.Do(func() {
+		close(w.done)
+		if w.notifier != nil {
+			err = w.notifier.Close()
+		}
+		<-w.stopped
+	})
+	return err
+}
+
+func (w *Watcher[T]) run() {
+	defer close(w.stopped)
+
+	var events <-chan struct{}
+	var poll <-chan time.Time
+	startPolling := func() {
+		ticker := time.NewTicker(w.options.pollInterval)
+		go func() {
+			<-w.stopped
+			ticker.Stop()
+		}()
+		poll = ticker.C
+	}
+	if w.notifier != nil {
+		events = w.notifier.Events()
+	} else {
+		startPolling()
+	}
+
+	// Writes usually cause multiple events, so we wait until they settle.
+	debounce := time.NewTimer(w.options.debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				select {
+				case <-w.done:
+					return
+				default:
+				}
+				// The notifier stopped working unexpectedly.
+				startPolling()
+				continue
+			}
+			debounce.Reset(w.options.debounce)
+		case <-debounce.C:
+			w.reloadIfChanged()
+		case <-poll:
+			w.reloadIfChanged()
+		}
+	}
+}
+
+func (w *Watcher[T]) readContents() [][]byte {
+	contents := make([][]byte, 0, len(w.paths))
+	for _, path := range w.paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			content = nil
+		} else if content == nil {
+			// Distinguish empty files from missing ones.
+			content = []byte{}
+		}
+		contents = append(contents, content)
+	}
+	return contents
+}
+
+func (w *Watcher[T]) reloadIfChanged() {
+	contents := w.readContents()
+	changed := false
+	for index, content := range contents {
+		if (content == nil) != (w.contents[index] == nil) || !bytes.Equal(content, w.contents[index]) {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return
+	}
+	w.contents = contents
+
+	reload := Reload[T]{Old: w.Current()}
+	reload.New, reload.Err = w.parse()
+	if reload.Err == nil {
+		w.mutex.Lock()
+		w.current = reload.New
+		w.mutex.Unlock()
+	}
+
+	if w.onReload != nil {
+		w.onReload(reload)
+	}
+}
@@ -0,0 +1,68 @@
+//go:build linux
+
+package env
+
+import (
+	"os"
+	"syscall"
+)
+
+// inotifyMask covers all ways a file inside a directory can be changed,
+// including being replaced via rename.
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_ATTRIB |
+	syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+type inotifyNotifier struct {
+	file   *os.File
+	events chan struct{}
+}
+
+// newNotifier watches the given directories using inotify.
+func newNotifier(directories []string) (notifier, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	for _, directory := range directories {
+		if _, err := syscall.InotifyAddWatch(fd, directory, inotifyMask); err != nil {
+			syscall.Close(fd)
+			return nil, err
+		}
+	}
+
+	n := &inotifyNotifier{
+		// Since the descriptor is non-blocking, the file uses the runtime
+		// poller, allowing Close to interrupt pending reads.
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan struct{}, 1),
+	}
+	go n.run()
+	return n, nil
+}
+
+func (n *inotifyNotifier) run() {
+	defer close(n.events)
+
+	// We don't care which file has changed, as the watcher compares the
+	// contents of all watched files anyway.
+	buffer := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		if _, err := n.file.Read(buffer); err != nil {
+			return
+		}
+		select {
+		case n.events <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Events implements notifier.Events.
+func (n *inotifyNotifier) Events() <-chan struct{} {
+	return n.events
+}
+
+// Close implements notifier.Close.
+func (n *inotifyNotifier) Close() error {
+	return n.file.Close()
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package env
+
+// newNotifier isn't implemented for this platform, so files are polled.
+func newNotifier([]string) (notifier, error) {
+	return nil, errNotifierUnsupported
+}